@@ -0,0 +1,136 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+
+	"github.com/s0rg/array2d"
+)
+
+// Rule is a cellular-automaton rule, it receives cell, its value and values of in-bounds neighbours,
+// and returns value for the next generation.
+type Rule[T any] func(p image.Point, val T, neighbours []T) T
+
+// CaveConfig holds settings for [Map.GenerateCave], zero values are replaced with defaults of classic 4-5 rule.
+type CaveConfig struct {
+	// Fill is a chance of cell to be a wall initially, defaults to 0.45.
+	Fill float64
+	// Steps is a number of generations, defaults to 5.
+	Steps int
+	// Birth is a minimal number of wall neighbours for floor cell to become a wall, defaults to 5.
+	Birth int
+	// Survive is a minimal number of wall neighbours for wall cell to stay a wall, defaults to 4.
+	Survive int
+}
+
+// Step performs single cellular-automaton generation, rule is evaluated for every cell (with neighbours in given
+// directions) against current state and results are written into second buffer, that becomes new map state.
+func (m *Map[T]) Step(
+	dirs []image.Point,
+	rule Rule[T],
+) {
+	var (
+		next       = array2d.New[T](m.cells.Bounds())
+		neighbours = make([]T, 0, len(dirs))
+	)
+
+	m.cells.Iter(func(x, y int, v T) (ok bool) {
+		p := image.Pt(x, y)
+		neighbours = neighbours[:0]
+
+		m.Neighbours(p, dirs, func(_ image.Point, n T) bool {
+			neighbours = append(neighbours, n)
+
+			return true
+		})
+
+		next.Set(x, y, rule(p, v, neighbours))
+
+		return true
+	})
+
+	m.cells = next
+	m.notifyAll()
+}
+
+// GenerateCave fills map with cave by cellular automaton: cells are randomly walled (border ones are always
+// walls), then smoothed by birth/survive rule, where out-of-bounds neighbours count as walls. Only the largest
+// (by cardinal connectivity) cave is kept, disconnected pockets are walled. It returns number of floor cells.
+func (m *Map[T]) GenerateCave(
+	src rand.Source,
+	cfg CaveConfig,
+	wall, floor func() T,
+) (size int) {
+	const (
+		defaultFill    = 0.45
+		defaultSteps   = 5
+		defaultBirth   = 5
+		defaultSurvive = 4
+	)
+
+	var (
+		rng   = rand.New(src)
+		cave  = New[bool](m.rc)
+		dirs  = Points(DirectionsALL...)
+		inner = m.rc.Inset(1)
+	)
+
+	cfg.Fill = orDefault(cfg.Fill, defaultFill)
+	cfg.Steps = orDefault(cfg.Steps, defaultSteps)
+	cfg.Birth = orDefault(cfg.Birth, defaultBirth)
+	cfg.Survive = orDefault(cfg.Survive, defaultSurvive)
+
+	cave.cells.Iter(func(x, y int, _ bool) (next bool) {
+		cave.cells.Set(x, y, !image.Pt(x, y).In(inner) || rng.Float64() < cfg.Fill)
+
+		return true
+	})
+
+	for i := 0; i < cfg.Steps; i++ {
+		cave.Step(dirs, func(_ image.Point, wall bool, neighbours []bool) bool {
+			walls := len(dirs) - len(neighbours)
+
+			for _, n := range neighbours {
+				if n {
+					walls++
+				}
+			}
+
+			return walls >= cfg.Birth || (wall && walls >= cfg.Survive)
+		})
+	}
+
+	var (
+		comps   = cave.Components(Points(DirectionsCardinal...), func(_ image.Point, w bool) bool { return !w })
+		largest = NoLabel
+	)
+
+	for i, s := range comps.Sizes {
+		if largest == NoLabel || s > comps.Sizes[largest] {
+			largest = i
+		}
+	}
+
+	comps.Labels.Iter(func(p image.Point, label int) bool {
+		if label != NoLabel && label == largest {
+			m.Set(p, floor())
+			size++
+		} else {
+			m.Set(p, wall())
+		}
+
+		return true
+	})
+
+	return size
+}
+
+func orDefault[V comparable](v, def V) V {
+	var zero V
+
+	if v == zero {
+		return def
+	}
+
+	return v
+}
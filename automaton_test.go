@@ -0,0 +1,152 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"testing"
+)
+
+type countWatcher struct {
+	count  int
+	resets int
+}
+
+func (c *countWatcher) changed(_ image.Point) {
+	c.count++
+}
+
+func (c *countWatcher) reset() {
+	c.resets++
+}
+
+func lifeRule(_ image.Point, alive bool, neighbours []bool) bool {
+	var n int
+
+	for _, v := range neighbours {
+		if v {
+			n++
+		}
+	}
+
+	return n == 3 || (alive && n == 2)
+}
+
+func TestMapStep(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 5, 5
+
+	var (
+		m    = New[bool](image.Rect(0, 0, W, H))
+		dirs = Points(DirectionsALL...)
+		cw   = &countWatcher{}
+	)
+
+	// blinker
+	for x := 1; x <= 3; x++ {
+		m.Set(image.Pt(x, 2), true)
+	}
+
+	m.watch(cw)
+
+	m.Step(dirs, lifeRule)
+
+	for y := 0; y < H; y++ {
+		for x := 0; x < W; x++ {
+			if want := x == 2 && y >= 1 && y <= 3; m.MustGet(image.Pt(x, y)) != want {
+				t.Fatalf("(%d, %d) step 1", x, y)
+			}
+		}
+	}
+
+	if cw.count != 0 || cw.resets != 1 {
+		t.Fatal("notified:", cw.count, cw.resets)
+	}
+
+	m.unwatch(cw)
+	m.Step(dirs, lifeRule)
+
+	for x := 1; x <= 3; x++ {
+		if !m.MustGet(image.Pt(x, 2)) || m.MustGet(image.Pt(2, 1)) {
+			t.Fatal("step 2")
+		}
+	}
+
+	if cw.resets != 1 {
+		t.Fatal("notified after unwatch:", cw.resets)
+	}
+}
+
+func TestMapStepNeighbours(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 4, 3
+
+	m := New[int](image.Rect(0, 0, W, H))
+
+	m.Step(Points(DirectionsALL...), func(_ image.Point, _ int, neighbours []int) int {
+		return len(neighbours)
+	})
+
+	for p, want := range map[image.Point]int{{X: 0, Y: 0}: 3, {X: 1, Y: 0}: 5, {X: 1, Y: 1}: 8} {
+		if got := m.MustGet(p); got != want {
+			t.Fatalf("%v: got %d want %d", p, got, want)
+		}
+	}
+}
+
+func TestMapGenerateCave(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 60, 40
+
+	for seed := uint64(1); seed <= 6; seed++ {
+		var (
+			m     = New[bool](image.Rect(0, 0, W, H))
+			size  = m.GenerateCave(rand.NewPCG(seed, seed), CaveConfig{}, isTrue, isFalse)
+			c     = m.Components(Points(DirectionsCardinal...), isFloor)
+			inner = m.Rectangle().Inset(1)
+		)
+
+		if size < W*H/10 || len(c.Sizes) != 1 || c.Sizes[0] != size {
+			t.Fatalf("seed %d: size %d components %v", seed, size, c.Sizes)
+		}
+
+		m.Iter(func(p image.Point, wall bool) bool {
+			if !wall && !p.In(inner) {
+				t.Fatalf("seed %d: border %v", seed, p)
+			}
+
+			return true
+		})
+
+		var (
+			o    = New[bool](image.Rect(0, 0, W, H))
+			same = o.GenerateCave(rand.NewPCG(seed, seed), CaveConfig{}, isTrue, isFalse)
+		)
+
+		if same != size {
+			t.Fatalf("seed %d: not reproducible", seed)
+		}
+	}
+}
+
+func TestMapGenerateCaveSolid(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 10, 10
+
+	m := New[bool](image.Rect(0, 0, W, H))
+
+	if size := m.GenerateCave(rand.NewPCG(1, 1), CaveConfig{Fill: 1, Steps: 1}, isTrue, isFalse); size != 0 {
+		t.Fatal("size:", size)
+	}
+
+	m.Iter(func(p image.Point, wall bool) bool {
+		if !wall {
+			t.Fatalf("%v not a wall", p)
+		}
+
+		return true
+	})
+}
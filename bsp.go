@@ -0,0 +1,178 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+)
+
+// Dungeon is a result of level generation.
+type Dungeon struct {
+	// Rooms holds carved rooms.
+	Rooms []image.Rectangle
+	// Links holds indices of rooms (in ascending order), that every room is connected to by its own corridor.
+	Links [][]int
+}
+
+// BSPConfig holds settings for [Map.GenerateBSP].
+type BSPConfig struct {
+	// MinRoom is a minimal room side, defaults to one.
+	MinRoom int
+	// MinLeaf is a minimal partition side, it is at least two cells more than MinRoom (to hold walls).
+	MinLeaf int
+}
+
+type bspGen[T any] struct {
+	m       *Map[T]
+	rng     *rand.Rand
+	floor   func() T
+	dungeon *Dungeon
+	minRoom int
+	minLeaf int
+}
+
+// GenerateBSP fills map with walls, then carves rooms and corridors by binary space partitioning. Map is split
+// recursively, while partitions are large enough, every leaf gets a room, then rooms of sibling partitions are
+// connected by L-shaped corridors between their closest rooms. Corridors may cross other rooms, so map may be more
+// connected than links say.
+func (m *Map[T]) GenerateBSP(
+	src rand.Source,
+	cfg BSPConfig,
+	wall, floor func() T,
+) (rv *Dungeon) {
+	const walls = 2
+
+	minRoom := max(cfg.MinRoom, 1)
+
+	gen := &bspGen[T]{
+		m:       m,
+		rng:     rand.New(src),
+		floor:   floor,
+		dungeon: &Dungeon{},
+		minRoom: minRoom,
+		minLeaf: max(cfg.MinLeaf, minRoom+walls),
+	}
+
+	m.Fill(wall)
+	gen.split(m.rc)
+
+	rv = gen.dungeon
+
+	for _, links := range rv.Links {
+		slices.Sort(links)
+	}
+
+	return rv
+}
+
+// split partitions given area and returns indices of its rooms, area is split only if both parts can hold a room.
+func (g *bspGen[T]) split(rc image.Rectangle) (rooms []int) {
+	const (
+		ratio = 1.25
+		two   = 2
+	)
+
+	var (
+		w, h     = rc.Dx(), rc.Dy()
+		canW     = w >= two*g.minLeaf && h >= g.minLeaf
+		canH     = h >= two*g.minLeaf && w >= g.minLeaf
+		vertical bool
+	)
+
+	switch {
+	case !canW && !canH:
+		return g.room(rc)
+	case canW != canH:
+		vertical = canW
+	case float64(w) > float64(h)*ratio:
+		vertical = true
+	case float64(h) > float64(w)*ratio:
+		vertical = false
+	default:
+		vertical = g.rng.IntN(two) == 0
+	}
+
+	var a, b image.Rectangle
+
+	if vertical {
+		at := rc.Min.X + g.minLeaf + g.rng.IntN(w-two*g.minLeaf+1)
+		a, b = image.Rect(rc.Min.X, rc.Min.Y, at, rc.Max.Y), image.Rect(at, rc.Min.Y, rc.Max.X, rc.Max.Y)
+	} else {
+		at := rc.Min.Y + g.minLeaf + g.rng.IntN(h-two*g.minLeaf+1)
+		a, b = image.Rect(rc.Min.X, rc.Min.Y, rc.Max.X, at), image.Rect(rc.Min.X, at, rc.Max.X, rc.Max.Y)
+	}
+
+	left, right := g.split(a), g.split(b)
+
+	g.connect(left, right)
+
+	return append(left, right...)
+}
+
+// room carves random room within leaf, keeping walls around.
+func (g *bspGen[T]) room(leaf image.Rectangle) (rooms []int) {
+	inner := leaf.Inset(1)
+
+	if inner.Dx() < g.minRoom || inner.Dy() < g.minRoom {
+		return nil
+	}
+
+	var (
+		w  = g.minRoom + g.rng.IntN(inner.Dx()-g.minRoom+1)
+		h  = g.minRoom + g.rng.IntN(inner.Dy()-g.minRoom+1)
+		x  = inner.Min.X + g.rng.IntN(inner.Dx()-w+1)
+		y  = inner.Min.Y + g.rng.IntN(inner.Dy()-h+1)
+		rc = image.Rect(x, y, x+w, y+h)
+	)
+
+	g.m.RectFilled(rc, func(p image.Point, _ T) bool {
+		g.m.Set(p, g.floor())
+
+		return true
+	})
+
+	g.dungeon.Rooms = append(g.dungeon.Rooms, rc)
+	g.dungeon.Links = append(g.dungeon.Links, nil)
+
+	return []int{len(g.dungeon.Rooms) - 1}
+}
+
+// connect joins closest rooms from given groups, both sides of split are at least leaf large, so groups are not empty.
+func (g *bspGen[T]) connect(left, right []int) {
+	const two = 2
+
+	var (
+		rooms      = g.dungeon.Rooms
+		ra, rb     = left[0], right[0]
+		best       = -1.0
+		center     = func(i int) image.Point { return rooms[i].Min.Add(rooms[i].Max).Div(two) }
+		from, to   image.Point
+		horizontal = g.rng.IntN(two) == 0
+	)
+
+	for _, a := range left {
+		for _, b := range right {
+			if d := DistanceManhattan(center(a), center(b)); best < 0 || d < best {
+				ra, rb, best = a, b, d
+			}
+		}
+	}
+
+	from, to = center(ra), center(rb)
+
+	elbow := image.Pt(from.X, to.Y)
+	if horizontal {
+		elbow = image.Pt(to.X, from.Y)
+	}
+
+	for _, seg := range [][2]image.Point{{from, elbow}, {elbow, to}} {
+		bresenham(seg[0], seg[1], func(p image.Point) bool {
+			g.m.Set(p, g.floor())
+
+			return true
+		})
+	}
+
+	g.dungeon.Links[ra] = append(g.dungeon.Links[ra], rb)
+	g.dungeon.Links[rb] = append(g.dungeon.Links[rb], ra)
+}
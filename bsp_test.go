@@ -0,0 +1,164 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func isTrue() bool  { return true }
+func isFalse() bool { return false }
+
+// linksConnected reports if links graph is connected.
+func linksConnected(links [][]int) bool {
+	if len(links) == 0 {
+		return true
+	}
+
+	seen := []int{0}
+
+	for i := 0; i < len(seen); i++ {
+		for _, n := range links[seen[i]] {
+			if !slices.Contains(seen, n) {
+				seen = append(seen, n)
+			}
+		}
+	}
+
+	return len(seen) == len(links)
+}
+
+func TestMapGenerateBSP(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 60, 40
+
+	cfg := BSPConfig{MinRoom: 4, MinLeaf: 8}
+
+	for seed := uint64(1); seed <= 8; seed++ {
+		var (
+			m = New[bool](image.Rect(0, 0, W, H))
+			d = m.GenerateBSP(rand.NewPCG(seed, seed), cfg, isTrue, isFalse)
+		)
+
+		if len(d.Rooms) < 4 || len(d.Links) != len(d.Rooms) {
+			t.Fatalf("seed %d: rooms %d links %d", seed, len(d.Rooms), len(d.Links))
+		}
+
+		var edges int
+
+		for i, rc := range d.Rooms {
+			if rc.Dx() < cfg.MinRoom || rc.Dy() < cfg.MinRoom || !rc.In(m.Rectangle().Inset(1)) {
+				t.Fatalf("seed %d: room %v", seed, rc)
+			}
+
+			for j := i + 1; j < len(d.Rooms); j++ {
+				if rc.Overlaps(d.Rooms[j]) {
+					t.Fatalf("seed %d: rooms %v and %v overlap", seed, rc, d.Rooms[j])
+				}
+			}
+
+			m.RectFilled(rc, func(p image.Point, wall bool) bool {
+				if wall {
+					t.Fatalf("seed %d: wall %v in room", seed, p)
+				}
+
+				return true
+			})
+
+			if !slices.IsSorted(d.Links[i]) {
+				t.Fatalf("seed %d: links %v", seed, d.Links[i])
+			}
+
+			edges += len(d.Links[i])
+		}
+
+		if edges != 2*(len(d.Rooms)-1) || !linksConnected(d.Links) {
+			t.Fatalf("seed %d: links %v", seed, d.Links)
+		}
+
+		if c := m.Components(Points(DirectionsCardinal...), isFloor); len(c.Sizes) != 1 {
+			t.Fatalf("seed %d: %d components", seed, len(c.Sizes))
+		}
+
+		m.Iter(func(p image.Point, wall bool) bool {
+			if !wall && !p.In(m.Rectangle().Inset(1)) {
+				t.Fatalf("seed %d: border %v carved", seed, p)
+			}
+
+			return true
+		})
+	}
+}
+
+func TestMapGenerateBSPSeed(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 40, 30
+
+	var (
+		a  = New[bool](image.Rect(0, 0, W, H))
+		b  = New[bool](image.Rect(0, 0, W, H))
+		c  = New[bool](image.Rect(0, 0, W, H))
+		da = a.GenerateBSP(rand.NewPCG(1, 2), BSPConfig{}, isTrue, isFalse)
+		db = b.GenerateBSP(rand.NewPCG(1, 2), BSPConfig{}, isTrue, isFalse)
+		dc = c.GenerateBSP(rand.NewPCG(2, 1), BSPConfig{}, isTrue, isFalse)
+	)
+
+	if !slices.Equal(da.Rooms, db.Rooms) || slices.Equal(da.Rooms, dc.Rooms) {
+		t.Fatal("rooms")
+	}
+
+	a.Iter(func(p image.Point, v bool) bool {
+		if v != b.MustGet(p) {
+			t.Fatalf("%v differs", p)
+		}
+
+		return true
+	})
+}
+
+func TestMapGenerateBSPSmall(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		Rect  image.Rectangle
+		Rooms int
+	}{
+		{Rect: image.Rect(0, 0, 4, 4), Rooms: 0},
+		{Rect: image.Rect(0, 0, 5, 5), Rooms: 1},
+		{Rect: image.Rect(0, 0, 10, 5), Rooms: 2},
+		{Rect: image.Rect(0, 0, 5, 10), Rooms: 2},
+	}
+
+	for i, tc := range cases {
+		m := New[bool](tc.Rect)
+
+		d := m.GenerateBSP(rand.NewPCG(1, 1), BSPConfig{MinRoom: 3}, isTrue, isFalse)
+		if len(d.Rooms) != tc.Rooms {
+			t.Fatalf("case[%d]: rooms %v", i, d.Rooms)
+		}
+	}
+}
+
+func TestMapGenerateBSPThin(t *testing.T) {
+	t.Parallel()
+
+	for _, rc := range []image.Rectangle{
+		image.Rect(0, 0, 2, 40),
+		image.Rect(0, 0, 1, 9),
+		image.Rect(0, 0, 40, 2),
+		image.Rect(0, 0, 3, 60),
+		image.Rect(0, 0, 60, 4),
+	} {
+		for seed := uint64(1); seed <= 5; seed++ {
+			m := New[bool](rc)
+
+			d := m.GenerateBSP(rand.NewPCG(seed, seed), BSPConfig{}, isTrue, isFalse)
+			if !linksConnected(d.Links) {
+				t.Fatalf("%v seed %d: not connected", rc, seed)
+			}
+		}
+	}
+}
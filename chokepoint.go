@@ -0,0 +1,450 @@
+package grid
+
+import (
+	"cmp"
+	"image"
+	"slices"
+)
+
+// Region is a part of map, that is separated by chokepoint.
+type Region struct {
+	// Seed is any cell of region, use it to flood-fill whole region.
+	Seed image.Point
+	// Size is a number of region cells.
+	Size int
+}
+
+// Chokepoint is a narrow passage, blocking all of its cells splits map into (at least two) regions.
+type Chokepoint struct {
+	Cells   []image.Point
+	Regions []Region
+}
+
+// Articulations finds articulation cells, passable cells, blocking of any of them disconnects its region
+// (as in [Map.Components]). Directions must be symmetric (see [Points]), chokepoints are returned in row-major order,
+// every one has exactly one cell. It runs in linear time (by Tarjan's algorithm).
+func (m *Map[T]) Articulations(
+	dirs []image.Point,
+	pass Iter[T],
+) (rv []Chokepoint) {
+	g := m.cutGraph(m.passable(pass), dirs, 1)
+
+	for i := range g.span {
+		g.span[i] = 1
+	}
+
+	rv = g.cuts()
+
+	slices.SortFunc(rv, func(a, b Chokepoint) int {
+		return cmpPoints(a.Cells[0], b.Cells[0])
+	})
+
+	return rv
+}
+
+// Chokepoints finds straight (horizontal or vertical) gates of passable cells up to given width, with impassable
+// (or out-of-bounds) cells at both ends, blocking of which splits map into regions. Every cross-section of narrow
+// corridor is a gate, so adjacent gates may separate same regions. Directions must be symmetric (see [Points]),
+// chokepoints are returned in row-major order of their first cells, horizontal ones first.
+// Gates of every axis are merged into single graph vertices, so it runs in linear time, unless directions miss
+// the axis itself (gate cells are not adjacent then, and every such gate is checked by flooding the map).
+func (m *Map[T]) Chokepoints(
+	dirs []image.Point,
+	width int,
+	pass Iter[T],
+) (rv []Chokepoint) {
+	var (
+		w, _ = m.cells.Bounds()
+		open = m.passable(pass)
+	)
+
+	for _, axis := range []image.Point{{X: 1}, {Y: 1}} {
+		var (
+			g     = m.cutGraph(open, dirs, axis.Y*w+axis.X)
+			merge = slices.Contains(dirs, axis)
+			split [][]image.Point
+		)
+
+		for _, gate := range m.gates(open, axis, width) {
+			if len(gate) > 1 && !merge {
+				split = append(split, gate)
+
+				continue
+			}
+
+			g.merge(gate)
+		}
+
+		rv = append(rv, g.cuts()...)
+		rv = append(rv, m.split(split, dirs, open)...)
+	}
+
+	slices.SortStableFunc(rv, func(a, b Chokepoint) int {
+		return cmpPoints(a.Cells[0], b.Cells[0])
+	})
+
+	return rv
+}
+
+// gates finds maximal straight runs of passable cells along given axis, that are not longer than width.
+func (m *Map[T]) gates(
+	open []bool,
+	axis image.Point,
+	width int,
+) (rv [][]image.Point) {
+	var (
+		w, _ = m.cells.Bounds()
+		east = image.Pt(1, 0)
+	)
+
+	isOpen := func(p image.Point) bool {
+		return p.In(m.rc) && open[p.Y*w+p.X]
+	}
+
+	m.cells.Iter(func(x, y int, _ T) (next bool) {
+		p := image.Pt(x, y)
+
+		if !isOpen(p) || isOpen(p.Sub(axis)) {
+			return true
+		}
+
+		// single cells, that are closed on both axes, are reported once
+		if axis.Y > 0 && !isOpen(p.Add(axis)) && !isOpen(p.Sub(east)) && !isOpen(p.Add(east)) {
+			return true
+		}
+
+		var gate []image.Point
+
+		for ; isOpen(p) && len(gate) <= width; p = p.Add(axis) {
+			gate = append(gate, p)
+		}
+
+		if len(gate) <= width {
+			rv = append(rv, gate)
+		}
+
+		return true
+	})
+
+	return rv
+}
+
+// split checks every given gate by flooding regions around it.
+func (m *Map[T]) split(
+	gates [][]image.Point,
+	dirs []image.Point,
+	open []bool,
+) (rv []Chokepoint) {
+	var (
+		w, _  = m.cells.Bounds()
+		mark  = make([]int, len(open))
+		stamp int
+	)
+
+	isOpen := func(p image.Point) bool {
+		return p.In(m.rc) && open[p.Y*w+p.X]
+	}
+
+	for _, gate := range gates {
+		stamp++
+
+		for _, p := range gate {
+			mark[p.Y*w+p.X] = stamp
+		}
+
+		var seeds []image.Point
+
+		for _, p := range gate {
+			for _, d := range dirs {
+				if s := p.Add(d); isOpen(s) && mark[s.Y*w+s.X] != stamp && !slices.Contains(seeds, s) {
+					seeds = append(seeds, s)
+				}
+			}
+		}
+
+		if parts := m.separate(seeds, dirs, isOpen, mark, &stamp); len(parts) > 1 {
+			rv = append(rv, Chokepoint{Cells: gate, Regions: parts})
+		}
+	}
+
+	return rv
+}
+
+// separate floods regions from given seeds, all of them are marked by current stamp, it stops early,
+// if first region holds all the seeds.
+func (m *Map[T]) separate(
+	seeds, dirs []image.Point,
+	isOpen func(image.Point) bool,
+	mark []int,
+	stamp *int,
+) (rv []Region) {
+	var (
+		w, _  = m.cells.Bounds()
+		gate  = *stamp
+		left  = len(seeds)
+		found = make(map[image.Point]bool, len(seeds))
+	)
+
+	for _, s := range seeds {
+		found[s] = false
+	}
+
+	for _, s := range seeds {
+		if found[s] {
+			continue
+		}
+
+		*stamp++
+
+		var (
+			label = *stamp
+			size  int
+		)
+
+		m.scanline(s, dirs, func(p image.Point) bool {
+			if !isOpen(p) {
+				return false
+			}
+
+			mk := mark[p.Y*w+p.X]
+
+			return mk != gate && mk != label
+		}, func(p image.Point) bool {
+			mark[p.Y*w+p.X] = label
+			size++
+
+			if seen, ok := found[p]; ok && !seen {
+				found[p] = true
+				left--
+			}
+
+			return len(rv) > 0 || left > 0
+		})
+
+		if len(rv) == 0 && left == 0 {
+			return nil
+		}
+
+		rv = append(rv, Region{Seed: s, Size: size})
+	}
+
+	return rv
+}
+
+// cutGraph is a graph of passable cells, where cells of every gate (straight run of adjacent cells) are merged
+// into single vertex, identified by its first cell. Only gates are reported as cut vertices.
+type cutGraph struct {
+	open []bool
+	dirs []image.Point
+	rep  []int // vertex of every cell
+	span []int // number of cells of gate vertices, zero for other ones
+	w, h int
+	step int // index offset of adjacent gate cells
+}
+
+func (m *Map[T]) cutGraph(open []bool, dirs []image.Point, step int) (rv *cutGraph) {
+	w, h := m.cells.Bounds()
+
+	rv = &cutGraph{
+		open: open,
+		dirs: dirs,
+		rep:  make([]int, len(open)),
+		span: make([]int, len(open)),
+		w:    w,
+		h:    h,
+		step: step,
+	}
+
+	for i := range rv.rep {
+		rv.rep[i] = i
+	}
+
+	return rv
+}
+
+func (g *cutGraph) merge(gate []image.Point) {
+	u := g.index(gate[0])
+
+	for _, p := range gate {
+		g.rep[g.index(p)] = u
+	}
+
+	g.span[u] = len(gate)
+}
+
+func (g *cutGraph) index(p image.Point) int {
+	return p.Y*g.w + p.X
+}
+
+func (g *cutGraph) point(i int) image.Point {
+	return image.Pt(i%g.w, i/g.w)
+}
+
+// cells returns number of cells, merged into vertex u.
+func (g *cutGraph) cells(u int) int {
+	return max(g.span[u], 1)
+}
+
+// neighbour returns vertex, adjacent to vertex u by its k-th cell and direction pair, or -1 if there is none.
+func (g *cutGraph) neighbour(u, k int) (v int) {
+	p := g.point(u + k/len(g.dirs)*g.step).Add(g.dirs[k%len(g.dirs)])
+
+	if p.X < 0 || p.Y < 0 || p.X >= g.w || p.Y >= g.h {
+		return -1
+	}
+
+	if v = g.rep[g.index(p)]; !g.open[v] || v == u {
+		return -1
+	}
+
+	return v
+}
+
+// cuts finds gate vertices, blocking of which disconnects their regions, by Tarjan's algorithm.
+func (g *cutGraph) cuts() (rv []Chokepoint) {
+	n := len(g.open)
+
+	t := &tarjan{
+		g:       g,
+		disc:    make([]int, n),
+		low:     make([]int, n),
+		size:    make([]int, n),
+		parent:  make([]int, n),
+		regions: make(map[int][]Region),
+	}
+
+	for root := 0; root < n; root++ {
+		if !g.open[root] || g.rep[root] != root || t.disc[root] > 0 {
+			continue
+		}
+
+		t.walk(root)
+
+		rv = t.chokepoints(root, rv)
+	}
+
+	return rv
+}
+
+type tarjanFrame struct {
+	vertex, next int
+}
+
+type tarjan struct {
+	g       *cutGraph
+	regions map[int][]Region
+	disc    []int
+	low     []int
+	size    []int
+	parent  []int
+	stack   []tarjanFrame
+	cuts    []int
+	timer   int
+}
+
+// walk runs depth-first search from given root, collecting cut vertices and regions they separate.
+func (t *tarjan) walk(root int) {
+	t.cuts = t.cuts[:0]
+	t.visit(root, -1)
+
+	for len(t.stack) > 0 {
+		top := &t.stack[len(t.stack)-1]
+		u := top.vertex
+
+		if top.next < t.g.cells(u)*len(t.g.dirs) {
+			v := t.g.neighbour(u, top.next)
+			top.next++
+
+			switch {
+			case v < 0:
+			case t.disc[v] == 0:
+				t.visit(v, u)
+			default:
+				t.low[u] = min(t.low[u], t.disc[v])
+			}
+
+			continue
+		}
+
+		t.stack = t.stack[:len(t.stack)-1]
+		t.leave(u)
+	}
+}
+
+func (t *tarjan) visit(v, parent int) {
+	t.timer++
+	t.disc[v], t.low[v], t.size[v], t.parent[v] = t.timer, t.timer, t.g.cells(v), parent
+	t.stack = append(t.stack, tarjanFrame{vertex: v})
+}
+
+func (t *tarjan) leave(v int) {
+	u := t.parent[v]
+	if u < 0 {
+		return
+	}
+
+	t.low[u] = min(t.low[u], t.low[v])
+	t.size[u] += t.size[v]
+
+	if t.low[v] >= t.disc[u] && t.g.span[u] > 0 {
+		if _, ok := t.regions[u]; !ok {
+			t.cuts = append(t.cuts, u)
+		}
+
+		t.regions[u] = append(t.regions[u], Region{Seed: t.g.point(v), Size: t.size[v]})
+	}
+}
+
+// chokepoints appends cut vertices, found by last walk from given root, to the list.
+func (t *tarjan) chokepoints(root int, rv []Chokepoint) []Chokepoint {
+	const minParts = 2 // root splits its region, only if it has many subtrees
+
+	for _, u := range t.cuts {
+		parts := t.regions[u]
+
+		switch {
+		case u != root:
+			rest := t.size[root] - t.g.cells(u)
+
+			for _, r := range parts {
+				rest -= r.Size
+			}
+
+			parts = append(parts, Region{Seed: t.g.point(t.parent[u]), Size: rest})
+		case len(parts) < minParts:
+			continue
+		}
+
+		cells := make([]image.Point, t.g.span[u])
+
+		for i := range cells {
+			cells[i] = t.g.point(u + i*t.g.step)
+		}
+
+		rv = append(rv, Chokepoint{Cells: cells, Regions: parts})
+	}
+
+	return rv
+}
+
+func (m *Map[T]) passable(pass Iter[T]) (rv []bool) {
+	w, h := m.cells.Bounds()
+
+	rv = make([]bool, w*h)
+
+	m.cells.Iter(func(x, y int, v T) (next bool) {
+		rv[y*w+x] = pass(image.Pt(x, y), v)
+
+		return true
+	})
+
+	return rv
+}
+
+func cmpPoints(a, b image.Point) int {
+	if c := cmp.Compare(a.Y, b.Y); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.X, b.X)
+}
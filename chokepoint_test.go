@@ -0,0 +1,190 @@
+package grid
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+// splitRegions returns sizes of regions, that given cells are connected to, with cells blocked.
+func splitRegions(m *Map[bool], dirs, cells []image.Point) (rv []int) {
+	c := m.Components(dirs, func(p image.Point, wall bool) bool {
+		return !wall && !slices.Contains(cells, p)
+	})
+
+	var labels []int
+
+	for _, p := range cells {
+		m.Neighbours(p, dirs, func(n image.Point, _ bool) bool {
+			if l := c.Labels.MustGet(n); l != NoLabel && !slices.Contains(labels, l) {
+				labels = append(labels, l)
+			}
+
+			return true
+		})
+	}
+
+	for _, l := range labels {
+		rv = append(rv, c.Sizes[l])
+	}
+
+	slices.Sort(rv)
+
+	return rv
+}
+
+func regionSizes(c *Chokepoint) (rv []int) {
+	for _, r := range c.Regions {
+		rv = append(rv, r.Size)
+	}
+
+	slices.Sort(rv)
+
+	return rv
+}
+
+func checkRegions(t *testing.T, m *Map[bool], dirs []image.Point, c *Chokepoint) {
+	t.Helper()
+
+	want := splitRegions(m, dirs, c.Cells)
+
+	if len(want) < 2 || !slices.Equal(want, regionSizes(c)) {
+		t.Fatalf("%v: regions %v want %v", c.Cells, regionSizes(c), want)
+	}
+
+	for _, r := range c.Regions {
+		if m.MustGet(r.Seed) || slices.Contains(c.Cells, r.Seed) {
+			t.Fatalf("%v: bad seed %v", c.Cells, r.Seed)
+		}
+	}
+}
+
+func TestMapArticulations(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 16, 12
+
+	for seed := uint64(1); seed <= 6; seed++ {
+		for _, dirs := range [][]image.Point{
+			Points(DirectionsCardinal...),
+			Points(DirectionsALL...),
+		} {
+			var (
+				m    = randomWalls(seed, W, H, 0.3)
+				cuts = m.Articulations(dirs, isFloor)
+				want []image.Point
+			)
+
+			m.Iter(func(p image.Point, wall bool) bool {
+				if !wall && len(splitRegions(m, dirs, []image.Point{p})) > 1 {
+					want = append(want, p)
+				}
+
+				return true
+			})
+
+			if len(cuts) != len(want) {
+				t.Fatalf("seed %d: got %d want %d", seed, len(cuts), len(want))
+			}
+
+			for i := range cuts {
+				if len(cuts[i].Cells) != 1 || cuts[i].Cells[0] != want[i] {
+					t.Fatalf("seed %d: got %v want %v", seed, cuts[i].Cells, want[i])
+				}
+
+				checkRegions(t, m, dirs, &cuts[i])
+			}
+		}
+	}
+}
+
+func TestMapChokepoints(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 13, 7
+
+	var (
+		m    = New[bool](image.Rect(0, 0, W, H))
+		dirs = Points(DirectionsCardinal...)
+	)
+
+	// two rooms, joined by door of width 2
+	for y := 0; y < H; y++ {
+		m.Set(image.Pt(6, y), y < 2 || y > 3)
+	}
+
+	cps := m.Chokepoints(dirs, 1, isFloor)
+	if len(cps) != 0 {
+		t.Fatal("width 1:", cps)
+	}
+
+	cps = m.Chokepoints(dirs, 2, isFloor)
+	if len(cps) != 1 {
+		t.Fatal("width 2:", cps)
+	}
+
+	if !slices.Equal(cps[0].Cells, []image.Point{{X: 6, Y: 2}, {X: 6, Y: 3}}) ||
+		!slices.Equal(regionSizes(&cps[0]), []int{42, 42}) {
+		t.Fatal("door:", cps[0])
+	}
+
+	// every full column or row of room splits it
+	for _, c := range m.Chokepoints(dirs, W, isFloor) {
+		checkRegions(t, m, dirs, &c)
+	}
+
+	if cps = m.Chokepoints(dirs, 0, isFloor); len(cps) != 0 {
+		t.Fatal("width 0:", cps)
+	}
+}
+
+func TestMapChokepointsRandom(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 20, 14
+
+	var total int
+
+	for seed := uint64(1); seed <= 6; seed++ {
+		for _, dirs := range [][]image.Point{
+			Points(DirectionsCardinal...),
+			Points(DirectionsALL...),
+			Points(DirectionsDiagonal...),
+		} {
+			var (
+				m     = randomWalls(seed, W, H, 0.35)
+				cps   = m.Chokepoints(dirs, 3, isFloor)
+				cuts  = m.Articulations(dirs, isFloor)
+				found int
+			)
+
+			for i := range cps {
+				c := &cps[i]
+
+				if len(c.Cells) == 0 || len(c.Cells) > 3 {
+					t.Fatal("width:", c.Cells)
+				}
+
+				if i > 0 && cmpPoints(cps[i-1].Cells[0], c.Cells[0]) > 0 {
+					t.Fatal("order")
+				}
+
+				checkRegions(t, m, dirs, c)
+
+				if len(c.Cells) == 1 {
+					found++
+
+					if !slices.ContainsFunc(cuts, func(a Chokepoint) bool { return a.Cells[0] == c.Cells[0] }) {
+						t.Fatalf("%v: not an articulation", c.Cells)
+					}
+				}
+			}
+
+			total += found
+		}
+	}
+
+	if total == 0 {
+		t.Fatal("no single cell chokepoints")
+	}
+}
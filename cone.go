@@ -0,0 +1,41 @@
+package grid
+
+import (
+	"image"
+	"math"
+)
+
+// FOVCone restricts wrapped field-of-view to the arc of given Width (in degrees), centered at Facing angle
+// (in degrees, zero is East and angles grow clockwise). Cells outside the arc are not reported, but still may
+// cast shadows into it: their opacity is taken from Blocks, they are transparent, if Blocks is nil.
+type FOVCone[T any] struct {
+	FOV    FOV[T]
+	Blocks Iter[T]
+	Facing float64
+	Width  float64
+}
+
+// Compute implements [FOV] interface.
+func (f FOVCone[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	f.FOV.Compute(m, src, distMax, func(p image.Point, dist float64, v T) bool {
+		if p.Eq(src) || inArc(src, p, f.Facing, f.Width) {
+			return cast(p, dist, v)
+		}
+
+		return f.Blocks == nil || !f.Blocks(p, v)
+	})
+}
+
+// inArc reports if center of cell p lies within arc of given width, centered at facing angle, as seen from src.
+func inArc(src, p image.Point, facing, width float64) bool {
+	if width >= maxDegrees {
+		return true
+	}
+
+	var (
+		d     = p.Sub(src)
+		delta = math.Abs(degrees(math.Atan2(float64(d.Y), float64(d.X))) - degrees(radians(facing)))
+	)
+
+	return math.Min(delta, maxDegrees-delta) <= width*half
+}
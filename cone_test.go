@@ -0,0 +1,154 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func TestMapLOSCone(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H  = 41, 41
+		dist  = 15.0
+		width = 90.0
+	)
+
+	var (
+		src  = image.Pt(20, 20)
+		m    = New[struct{}](image.Rect(0, 0, W, H))
+		seen = make(set.Unordered[image.Point])
+	)
+
+	m.LineOfSightCone(src, North.Angle(), width, dist, func(p image.Point, _ float64, _ struct{}) bool {
+		if !seen.Add(p) {
+			t.Fatalf("%v seen twice", p)
+		}
+
+		return true
+	})
+
+	m.Iter(func(p image.Point, _ struct{}) bool {
+		// rays may touch neighbour cells at the arc edges
+		slack := 2 * degrees(math.Atan2(1.5, DistanceEuclidean(src, p)))
+
+		switch {
+		case p.Eq(src) || DistanceEuclidean(src, p) > dist:
+		case inArc(src, p, North.Angle(), width-slack) && !seen.Has(p):
+			t.Fatalf("%v not seen", p)
+		case !inArc(src, p, North.Angle(), width+slack) && seen.Has(p):
+			t.Fatalf("%v seen", p)
+		}
+
+		return true
+	})
+
+	seen = make(set.Unordered[image.Point])
+
+	m.LineOfSightCone(src, 0, maxDegrees*2, dist, func(p image.Point, _ float64, _ struct{}) bool {
+		seen.Add(p)
+
+		return true
+	})
+
+	if !seen.Has(src.Add(image.Pt(0, 5))) || !seen.Has(src.Add(image.Pt(0, -5))) {
+		t.Fail()
+	}
+
+	m.LineOfSightCone(src, 0, 0, dist, func(_ image.Point, _ float64, _ struct{}) bool {
+		t.Fatal("zero width")
+
+		return true
+	})
+}
+
+func TestFOVCone(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H  = 41, 41
+		dist  = 15.0
+		width = 90.0
+	)
+
+	var (
+		src  = image.Pt(20, 20)
+		open = New[bool](image.Rect(0, 0, W, H))
+		maps = []*Map[bool]{open, randomWalls(3, W, H, 0.15)}
+	)
+
+	maps[1].Set(src, false)
+
+	visible := func(m *Map[bool], fov FOV[bool]) (rv set.Unordered[image.Point]) {
+		rv = make(set.Unordered[image.Point])
+
+		fov.Compute(m, src, dist, func(p image.Point, _ float64, w bool) bool {
+			rv.Add(p)
+
+			return !w
+		})
+
+		return rv
+	}
+
+	for name, algo := range fovAlgorithms() {
+		for _, m := range maps {
+			all := visible(m, algo)
+
+			for _, facing := range []float64{30, 200, SouthEast.Angle()} {
+				var (
+					fov  = FOVCone[bool]{FOV: algo, Blocks: isWall, Facing: facing, Width: width}
+					seen = visible(m, fov)
+				)
+
+				m.Iter(func(p image.Point, _ bool) bool {
+					if want := all.Has(p) && (p.Eq(src) || inArc(src, p, facing, width)); want != seen.Has(p) {
+						t.Fatalf("%s facing %f: %v visibility mismatch", name, facing, p)
+					}
+
+					return true
+				})
+
+				if m != open {
+					continue
+				}
+
+				// nothing blocks the light on open map
+				fov.Blocks = nil
+
+				if got := visible(m, fov); len(got) != len(seen) {
+					t.Fatalf("%s facing %f: nil blocks %d != %d", name, facing, len(got), len(seen))
+				}
+			}
+		}
+	}
+}
+
+func TestInArc(t *testing.T) {
+	t.Parallel()
+
+	src := image.Pt(5, 5)
+
+	var cases = []struct {
+		Point  image.Point
+		Facing float64
+		Width  float64
+		Want   bool
+	}{
+		{Point: image.Pt(6, 5), Facing: 0, Width: 10, Want: true},
+		{Point: image.Pt(6, 5), Facing: 350, Width: 30, Want: true},
+		{Point: image.Pt(6, 5), Facing: -20, Width: 30, Want: false},
+		{Point: image.Pt(5, 4), Facing: East.Angle(), Width: 90, Want: false},
+		{Point: image.Pt(5, 4), Facing: North.Angle(), Width: 90, Want: true},
+		{Point: image.Pt(4, 5), Facing: East.Angle(), Width: 360, Want: true},
+	}
+
+	for i, tc := range cases {
+		if got := inArc(src, tc.Point, tc.Facing, tc.Width); got != tc.Want {
+			t.Fatalf("case[%d] failed want: %t got: %t", i, tc.Want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package grid
+
+import (
+	"image"
+	"math"
+)
+
+const octants = 8
+
+type diamond[T any] struct {
+	m       *Map[T]
+	cast    Cast[T]
+	seen    map[image.Point]bool
+	src     image.Point
+	distMax float64
+	oct     int
+}
+
+// CastDiamond performs shadow-casting, where every cell that blocks the light is treated as a diamond,
+// inscribed into cell square. Cast is called exactly once for every visible cell, it must return false
+// for cells, that blocks the light.
+func (m *Map[T]) CastDiamond(
+	src image.Point,
+	distMax float64,
+	cast Cast[T],
+) {
+	val, ok := m.cells.Get(src.X, src.Y)
+	if !ok {
+		return
+	}
+
+	d := diamond[T]{
+		m:       m,
+		cast:    cast,
+		src:     src,
+		distMax: distMax,
+		seen:    make(map[image.Point]bool),
+	}
+
+	d.seen[src] = !cast(src, 0, val)
+
+	for d.oct = 0; d.oct < octants; d.oct++ {
+		d.scan(1, 0.0, one)
+	}
+}
+
+func (d *diamond[T]) scan(depth int, low, high float64) {
+	if low >= high || float64(depth) > d.distMax {
+		return
+	}
+
+	var (
+		fd      = float64(depth)
+		colMin  = max(0, int(math.Floor(low*fd-half))+1)
+		colMax  = min(depth, int(math.Ceil(high*fd+half))-1)
+		lit     bool
+		edgeLow float64
+	)
+
+	for col := colMin; col <= colMax; col++ {
+		if !d.blocks(octantPoint(d.src, d.oct, depth, col)) {
+			lit = true
+
+			continue
+		}
+
+		if edgeLow = (float64(col) - half) / fd; lit {
+			d.scan(depth+1, low, edgeLow)
+		}
+
+		low, lit = math.Max(low, (float64(col)+half)/fd), false
+	}
+
+	if lit {
+		d.scan(depth+1, low, high)
+	}
+}
+
+// blocks reports cell to caller (if not reported yet) and returns true if it blocks the light.
+func (d *diamond[T]) blocks(pt image.Point) bool {
+	dist := DistanceEuclidean(d.src, pt)
+	if dist > d.distMax {
+		return true
+	}
+
+	val, ok := d.m.cells.Get(pt.X, pt.Y)
+	if !ok {
+		return true
+	}
+
+	wall, seen := d.seen[pt]
+	if !seen {
+		wall = !d.cast(pt, dist, val)
+		d.seen[pt] = wall
+	}
+
+	return wall
+}
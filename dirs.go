@@ -1,6 +1,9 @@
 package grid
 
-import "image"
+import (
+	"image"
+	"math"
+)
 
 type dir uint8
 
@@ -97,3 +100,10 @@ func (d dir) Invert() (rv dir) {
 
 	return North
 }
+
+// Angle returns direction angle (in degrees), as used by ray-casting: zero is East and angles grow clockwise.
+func (d dir) Angle() (deg float64) {
+	p := coords[d]
+
+	return degrees(math.Atan2(float64(p.Y), float64(p.X)))
+}
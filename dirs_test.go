@@ -1,6 +1,7 @@
 package grid
 
 import (
+	"math"
 	"testing"
 )
 
@@ -21,3 +22,25 @@ func TestDirsPoints(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestDirsAngle(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		Dir   dir
+		Angle float64
+	}{
+		{Dir: East, Angle: 0},
+		{Dir: SouthEast, Angle: 45},
+		{Dir: South, Angle: 90},
+		{Dir: West, Angle: 180},
+		{Dir: North, Angle: 270},
+		{Dir: NorthEast, Angle: 315},
+	}
+
+	for i, tc := range cases {
+		if a := tc.Dir.Angle(); math.Abs(a-tc.Angle) > 1e-9 {
+			t.Fatalf("case[%d] failed want: %f got: %f", i, tc.Angle, a)
+		}
+	}
+}
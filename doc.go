@@ -1,4 +1,6 @@
 /*
 Package grid implements generic 2D grid for ray-casting and path-finding.
+
+Generators get all their randomness from given [math/rand/v2.Source], so same source state always gives same result.
 */
 package grid
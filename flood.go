@@ -0,0 +1,195 @@
+package grid
+
+import (
+	"image"
+
+	"github.com/s0rg/array2d"
+)
+
+// NoLabel marks cells, that does not belong to any component.
+const NoLabel = -1
+
+const (
+	fillUnknown uint8 = iota
+	fillMatch
+	fillReject
+	fillDone
+)
+
+// Components holds connected components of map cells.
+type Components struct {
+	// Labels holds component index for every cell, or [NoLabel].
+	Labels *Map[int]
+	// Sizes holds number of cells for every component.
+	Sizes []int
+	// Bounds holds bounding rectangle for every component.
+	Bounds []image.Rectangle
+}
+
+// FloodFill iterates cells, that are connected to source one in given directions and match given predicate,
+// by scanline algorithm. Predicate is called at most once for every cell, cells are visited span by span.
+func (m *Map[T]) FloodFill(
+	src image.Point,
+	dirs []image.Point,
+	match, iter Iter[T],
+) {
+	state := array2d.New[uint8](m.cells.Bounds())
+
+	m.scanline(src, dirs, func(p image.Point) bool {
+		v, ok := m.cells.Get(p.X, p.Y)
+		if !ok {
+			return false
+		}
+
+		switch s, _ := state.Get(p.X, p.Y); s {
+		case fillMatch:
+			return true
+		case fillUnknown:
+			if match(p, v) {
+				state.Set(p.X, p.Y, fillMatch)
+
+				return true
+			}
+
+			state.Set(p.X, p.Y, fillReject)
+		}
+
+		return false
+	}, func(p image.Point) bool {
+		state.Set(p.X, p.Y, fillDone)
+
+		return iter(p, m.MustGet(p))
+	})
+}
+
+// Components labels connected (in given directions) components of cells, that match given predicate.
+// Components are numbered from zero, in row-major order of their top-left cells.
+func (m *Map[T]) Components(
+	dirs []image.Point,
+	match Iter[T],
+) (rv *Components) {
+	var (
+		w, h = m.cells.Bounds()
+		mask = array2d.New[bool](w, h)
+	)
+
+	rv = &Components{
+		Labels: New[int](m.rc),
+	}
+
+	rv.Labels.Fill(func() int {
+		return NoLabel
+	})
+
+	m.cells.Iter(func(x, y int, v T) (next bool) {
+		mask.Set(x, y, match(image.Pt(x, y), v))
+
+		return true
+	})
+
+	test := func(p image.Point) bool {
+		ok, _ := mask.Get(p.X, p.Y)
+
+		return ok && rv.Labels.MustGet(p) == NoLabel
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src := image.Pt(x, y)
+
+			if !test(src) {
+				continue
+			}
+
+			var (
+				label = len(rv.Sizes)
+				size  int
+				rc    = image.Rectangle{Min: src, Max: src.Add(image.Pt(1, 1))}
+			)
+
+			m.scanline(src, dirs, test, func(p image.Point) bool {
+				rv.Labels.cells.Set(p.X, p.Y, label)
+				rc = rc.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+				size++
+
+				return true
+			})
+
+			rv.Sizes = append(rv.Sizes, size)
+			rv.Bounds = append(rv.Bounds, rc)
+		}
+	}
+
+	return rv
+}
+
+// scanline fills cells, connected to source one, test must report false for out-of-bounds and already filled cells.
+// Spans are grown along east and west directions (if any given), other directions are used to seed next spans.
+func (m *Map[T]) scanline(
+	src image.Point,
+	dirs []image.Point,
+	test, fill func(image.Point) bool,
+) {
+	var (
+		west, east = image.Pt(-1, 0), image.Pt(1, 0)
+		left       bool
+		right      bool
+		seeds      = make([]image.Point, 0, len(dirs))
+	)
+
+	for _, d := range dirs {
+		switch d {
+		case west:
+			left = true
+		case east:
+			right = true
+		case image.Point{}:
+		default:
+			seeds = append(seeds, d)
+		}
+	}
+
+	stack := []image.Point{src}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !test(p) {
+			continue
+		}
+
+		x1, x2 := p.X, p.X
+
+		for left && test(image.Pt(x1-1, p.Y)) {
+			x1--
+		}
+
+		for right && test(image.Pt(x2+1, p.Y)) {
+			x2++
+		}
+
+		for x := x1; x <= x2; x++ {
+			if !fill(image.Pt(x, p.Y)) {
+				return
+			}
+		}
+
+		for _, d := range seeds {
+			run := false
+
+			for x := x1 + d.X; x <= x2+d.X; x++ {
+				q := image.Pt(x, p.Y+d.Y)
+
+				switch {
+				case !test(q):
+					run = false
+				case !run || !right:
+					// with eastward growth, the whole run is filled from its first cell
+					stack = append(stack, q)
+					run = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package grid
+
+import (
+	"image"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func isFloor(_ image.Point, wall bool) bool {
+	return !wall
+}
+
+// floodBFS is a reference flood fill.
+func floodBFS(m *Map[bool], src image.Point, dirs []image.Point) (rv set.Unordered[image.Point]) {
+	rv = make(set.Unordered[image.Point])
+
+	if v, ok := m.Get(src); !ok || v {
+		return rv
+	}
+
+	rv.Add(src)
+
+	for queue := []image.Point{src}; len(queue) > 0; queue = queue[1:] {
+		m.Neighbours(queue[0], dirs, func(p image.Point, wall bool) bool {
+			if !wall && rv.Add(p) {
+				queue = append(queue, p)
+			}
+
+			return true
+		})
+	}
+
+	return rv
+}
+
+func TestMapFloodFill(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 40, 30
+
+	var cases = [][]image.Point{
+		Points(DirectionsCardinal...),
+		Points(DirectionsALL...),
+		Points(DirectionsDiagonal...),
+		Points(West, North, South),
+		Points(East, NorthWest),
+		{{X: 2, Y: 1}, {X: -2, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 0}},
+	}
+
+	for seed := uint64(1); seed <= 5; seed++ {
+		m := randomWalls(seed, W, H, 0.35)
+
+		for i, dirs := range cases {
+			for _, src := range []image.Point{{X: 0, Y: 0}, {X: W / 2, Y: H / 2}, {X: W - 1, Y: 3}} {
+				var (
+					want  = floodBFS(m, src, dirs)
+					got   = make(set.Unordered[image.Point])
+					calls = make(set.Unordered[image.Point])
+				)
+
+				m.FloodFill(src, dirs, func(p image.Point, wall bool) bool {
+					if !calls.Add(p) {
+						t.Fatalf("case[%d]: predicate called twice for %v", i, p)
+					}
+
+					return !wall
+				}, func(p image.Point, _ bool) bool {
+					if !got.Add(p) {
+						t.Fatalf("case[%d]: %v visited twice", i, p)
+					}
+
+					return true
+				})
+
+				if len(got) != len(want) {
+					t.Fatalf("seed %d case[%d] %v: got %d want %d", seed, i, src, len(got), len(want))
+				}
+
+				for p := range want {
+					if !got.Has(p) {
+						t.Fatalf("seed %d case[%d] %v: %v not visited", seed, i, src, p)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMapFloodFillBreak(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 10, 10
+
+	var (
+		m     = New[bool](image.Rect(0, 0, W, H))
+		dirs  = Points(DirectionsCardinal...)
+		count int
+	)
+
+	m.FloodFill(image.Pt(5, 5), dirs, isFloor, func(_ image.Point, _ bool) bool {
+		count++
+
+		return count < 15
+	})
+
+	if count != 15 {
+		t.Fatal("count:", count)
+	}
+
+	m.FloodFill(image.Pt(-1, 5), dirs, isFloor, func(_ image.Point, _ bool) bool {
+		t.Fatal("out-of-bounds visit")
+
+		return true
+	})
+}
+
+func TestMapComponents(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 40, 30
+
+	for _, dirs := range [][]image.Point{
+		Points(DirectionsCardinal...),
+		Points(DirectionsALL...),
+		Points(North, South),
+	} {
+		var (
+			m     = randomWalls(7, W, H, 0.4)
+			c     = m.Components(dirs, isFloor)
+			prev  image.Point
+			total int
+		)
+
+		if len(c.Sizes) != len(c.Bounds) || len(c.Sizes) < 2 {
+			t.Fatal("components:", len(c.Sizes))
+		}
+
+		for label, size := range c.Sizes {
+			total += size
+
+			var (
+				first image.Point
+				found bool
+			)
+
+			c.Labels.Iter(func(p image.Point, l int) bool {
+				if l == label && (!found || p.Y < first.Y || (p.Y == first.Y && p.X < first.X)) {
+					first, found = p, true
+				}
+
+				return true
+			})
+
+			want := floodBFS(m, first, dirs)
+
+			if len(want) != size {
+				t.Fatalf("label %d: size %d want %d", label, size, len(want))
+			}
+
+			rc := image.Rectangle{Min: first, Max: first.Add(image.Pt(1, 1))}
+
+			for p := range want {
+				if c.Labels.MustGet(p) != label {
+					t.Fatalf("label %d: %v has label %d", label, p, c.Labels.MustGet(p))
+				}
+
+				rc = rc.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+			}
+
+			if rc != c.Bounds[label] {
+				t.Fatalf("label %d: bounds %v want %v", label, c.Bounds[label], rc)
+			}
+
+			if label > 0 && (first.Y < prev.Y || (first.Y == prev.Y && first.X < prev.X)) {
+				t.Fatalf("label %d: order", label)
+			}
+
+			prev = first
+		}
+
+		m.Iter(func(p image.Point, wall bool) bool {
+			if wall {
+				total++
+
+				if c.Labels.MustGet(p) != NoLabel {
+					t.Fatalf("wall %v labelled", p)
+				}
+			}
+
+			return true
+		})
+
+		if total != W*H {
+			t.Fatal("total:", total)
+		}
+	}
+}
+
+func TestMapComponentsConnected(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 8, 8
+
+	m := New[bool](image.Rect(0, 0, W, H))
+
+	if c := m.Components(Points(DirectionsCardinal...), isFloor); len(c.Sizes) != 1 || c.Sizes[0] != W*H {
+		t.Fatal("not connected")
+	}
+
+	for y := 0; y < H; y++ {
+		m.Set(image.Pt(3, y), true)
+	}
+
+	if c := m.Components(Points(DirectionsALL...), isFloor); len(c.Sizes) != 2 ||
+		c.Bounds[0] != image.Rect(0, 0, 3, H) || c.Bounds[1] != image.Rect(4, 0, W, H) {
+		t.Fatal("split", c.Sizes, c.Bounds)
+	}
+}
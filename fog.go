@@ -0,0 +1,203 @@
+package grid
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"slices"
+
+	"github.com/s0rg/array2d"
+)
+
+// FogState is a fog-of-war cell state.
+type FogState uint8
+
+const (
+	// FogHidden marks cells, that never been seen.
+	FogHidden FogState = 0
+	// FogExplored marks cells, that have been seen at least once.
+	FogExplored FogState = 1 << 0
+	// FogVisible marks cells, that are visible now.
+	FogVisible FogState = 1 << 1
+)
+
+var (
+	// ErrFogBounds is returned, when serialized fog does not match map bounds.
+	ErrFogBounds = errors.New("grid: fog bounds mismatch")
+	// ErrFogData is returned, when serialized fog is malformed.
+	ErrFogData = errors.New("grid: invalid fog data")
+)
+
+// Visible reports if cell is visible now.
+func (s FogState) Visible() bool {
+	return s&FogVisible != 0
+}
+
+// Explored reports if cell has been seen at least once.
+func (s FogState) Explored() bool {
+	return s&FogExplored != 0
+}
+
+// Fog is a fog-of-war, it tracks visible-now and ever-seen cells for multiple factions.
+type Fog[T any] struct {
+	m      *Map[T]
+	layers map[int]*array2d.Array[FogState]
+}
+
+// NewFog creates empty [Fog] for given map.
+func NewFog[T any](m *Map[T]) (rv *Fog[T]) {
+	return &Fog[T]{
+		m:      m,
+		layers: make(map[int]*array2d.Array[FogState]),
+	}
+}
+
+// Clear starts new turn for given faction: all its cells become not visible, explored ones are kept.
+func (f *Fog[T]) Clear(faction int) {
+	layer, ok := f.layers[faction]
+	if !ok {
+		return
+	}
+
+	layer.Iter(func(x, y int, s FogState) (next bool) {
+		if s.Visible() {
+			layer.Set(x, y, s&^FogVisible)
+		}
+
+		return true
+	})
+}
+
+// Reveal merges results of given field-of-view algorithm into faction state, cast is called for visible cells,
+// as in [FOV] and only cells, that are reported to it, are marked as visible.
+func (f *Fog[T]) Reveal(
+	faction int,
+	fov FOV[T],
+	src image.Point,
+	distMax float64,
+	cast Cast[T],
+) {
+	layer := f.layer(faction)
+
+	fov.Compute(f.m, src, distMax, func(p image.Point, dist float64, v T) bool {
+		layer.Set(p.X, p.Y, FogVisible|FogExplored)
+
+		return cast(p, dist, v)
+	})
+}
+
+// Mark marks cell visible for given faction.
+func (f *Fog[T]) Mark(faction int, p image.Point) (ok bool) {
+	if _, ok = f.m.cells.Get(p.X, p.Y); !ok {
+		return false
+	}
+
+	return f.layer(faction).Set(p.X, p.Y, FogVisible|FogExplored)
+}
+
+// State returns cell state for given faction.
+func (f *Fog[T]) State(faction int, p image.Point) (s FogState) {
+	if layer, ok := f.layers[faction]; ok {
+		s, _ = layer.Get(p.X, p.Y)
+	}
+
+	return s
+}
+
+// Factions returns known factions, in ascending order.
+func (f *Fog[T]) Factions() (rv []int) {
+	rv = make([]int, 0, len(f.layers))
+
+	for id := range f.layers {
+		rv = append(rv, id)
+	}
+
+	slices.Sort(rv)
+
+	return rv
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler] interface.
+func (f *Fog[T]) MarshalBinary() (data []byte, err error) {
+	w, h := f.m.cells.Bounds()
+
+	data = binary.AppendUvarint(data, uint64(w))
+	data = binary.AppendUvarint(data, uint64(h))
+	data = binary.AppendUvarint(data, uint64(len(f.layers)))
+
+	for _, id := range f.Factions() {
+		layer := f.layers[id]
+
+		data = binary.AppendVarint(data, int64(id))
+
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				s, _ := layer.Get(x, y)
+				data = append(data, byte(s))
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler] interface, fog must be bound to the map with same bounds.
+func (f *Fog[T]) UnmarshalBinary(data []byte) (err error) {
+	var (
+		w, h   = f.m.cells.Bounds()
+		header [3]uint64
+		n      int
+	)
+
+	for i := range header {
+		if header[i], n = binary.Uvarint(data); n <= 0 {
+			return ErrFogData
+		}
+
+		data = data[n:]
+	}
+
+	if header[0] != uint64(w) || header[1] != uint64(h) {
+		return ErrFogBounds
+	}
+
+	layers := make(map[int]*array2d.Array[FogState], header[2])
+
+	for i := uint64(0); i < header[2]; i++ {
+		id, n := binary.Varint(data)
+		if n <= 0 || len(data[n:]) < w*h {
+			return ErrFogData
+		}
+
+		data = data[n:]
+		layer := array2d.New[FogState](w, h)
+
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				layer.Set(x, y, FogState(data[y*w+x]))
+			}
+		}
+
+		data = data[w*h:]
+		layers[int(id)] = &layer
+	}
+
+	if len(data) > 0 {
+		return ErrFogData
+	}
+
+	f.layers = layers
+
+	return nil
+}
+
+func (f *Fog[T]) layer(faction int) (rv *array2d.Array[FogState]) {
+	rv, ok := f.layers[faction]
+	if !ok {
+		layer := array2d.New[FogState](f.m.cells.Bounds())
+		rv = &layer
+		f.layers[faction] = rv
+	}
+
+	return rv
+}
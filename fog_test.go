@@ -0,0 +1,121 @@
+package grid
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestFog(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H      = 20, 10
+		dist      = 3.0
+		red, blue = 1, -2
+	)
+
+	var (
+		m    = New[bool](image.Rect(0, 0, W, H))
+		f    = NewFog(m)
+		fov  = FOVDiamond[bool]{}
+		cast = func(_ image.Point, _ float64, w bool) bool { return !w }
+	)
+
+	f.Reveal(red, fov, image.Pt(2, 2), dist, cast)
+	f.Reveal(red, fov, image.Pt(8, 2), dist, cast)
+
+	if s := f.State(red, image.Pt(2, 3)); !s.Visible() || !s.Explored() {
+		t.Fatal("step 1 - visible", s)
+	}
+
+	if s := f.State(red, image.Pt(9, 2)); !s.Visible() {
+		t.Fatal("step 1 - second viewer", s)
+	}
+
+	if s := f.State(red, image.Pt(15, 5)); s != FogHidden {
+		t.Fatal("step 1 - hidden", s)
+	}
+
+	if s := f.State(blue, image.Pt(2, 3)); s != FogHidden {
+		t.Fatal("step 1 - other faction", s)
+	}
+
+	// next turn
+	f.Clear(red)
+	f.Clear(blue)
+	f.Reveal(red, fov, image.Pt(15, 5), dist, cast)
+
+	if s := f.State(red, image.Pt(2, 3)); s.Visible() || !s.Explored() {
+		t.Fatal("step 2 - explored", s)
+	}
+
+	if s := f.State(red, image.Pt(15, 6)); !s.Visible() {
+		t.Fatal("step 2 - visible", s)
+	}
+
+	if !f.Mark(blue, image.Pt(0, 0)) || f.Mark(blue, image.Pt(W, H)) {
+		t.Fatal("step 3 - mark")
+	}
+
+	if !f.State(blue, image.Pt(0, 0)).Visible() {
+		t.Fatal("step 3 - marked")
+	}
+
+	if fs := f.Factions(); len(fs) != 2 || fs[0] != blue || fs[1] != red {
+		t.Fatal("step 4 - factions", fs)
+	}
+}
+
+func TestFogMarshal(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 7, 5
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		f   = NewFog(m)
+		fov = NewFOVSymmetric(isWall)
+	)
+
+	f.Reveal(3, fov, image.Pt(1, 1), 2, func(_ image.Point, _ float64, _ bool) bool { return true })
+	f.Clear(3)
+	f.Mark(3, image.Pt(6, 4))
+	f.Mark(-9, image.Pt(5, 0))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewFog(m)
+
+	if err = g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int{3, -9, 0} {
+		m.Iter(func(p image.Point, _ bool) bool {
+			if f.State(id, p) != g.State(id, p) {
+				t.Fatalf("faction %d: state mismatch at %v", id, p)
+			}
+
+			return true
+		})
+	}
+
+	if err = NewFog(New[bool](image.Rect(0, 0, H, W))).UnmarshalBinary(data); !errors.Is(err, ErrFogBounds) {
+		t.Fatal("bounds", err)
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		data[:3],
+		append(data[:len(data):len(data)], 0),
+	} {
+		if err = g.UnmarshalBinary(bad); !errors.Is(err, ErrFogData) {
+			t.Fatal("data", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package grid
+
+import "image"
+
+// FOV is a field-of-view algorithm, cast is called for visible cells and must return false for cells,
+// that blocks the light. All algorithms need only cast callback, except symmetric one, which needs opacity
+// of cells, that are not visible, at creation (see [NewFOVSymmetric]).
+type FOV[T any] interface {
+	Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T])
+}
+
+// FOVRays is a ray-based field-of-view, see [Map.LineOfSight].
+type FOVRays[T any] struct{}
+
+// Compute implements [FOV] interface.
+func (FOVRays[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	m.LineOfSight(src, distMax, cast)
+}
+
+// FOVShadow is a recursive shadow-casting field-of-view, see [Map.CastShadow].
+type FOVShadow[T any] struct{}
+
+// Compute implements [FOV] interface.
+func (FOVShadow[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	m.CastShadow(src, distMax, cast)
+}
+
+// NewFOVSymmetric creates symmetric shadow-casting field-of-view, see [Map.CastShadowSymmetric].
+// Algorithm needs opacity of cells, that are not visible, so it is taken from given blocks callback
+// and result of cast is ignored.
+func NewFOVSymmetric[T any](blocks Iter[T]) FOV[T] {
+	return fovSymmetric[T]{blocks: blocks}
+}
+
+type fovSymmetric[T any] struct {
+	blocks Iter[T]
+}
+
+// Compute implements [FOV] interface.
+func (f fovSymmetric[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	m.CastShadowSymmetric(src, distMax, f.blocks, cast)
+}
+
+// FOVPermissive is a precise permissive field-of-view, see [Map.CastPermissive].
+type FOVPermissive[T any] struct{}
+
+// Compute implements [FOV] interface.
+func (FOVPermissive[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	m.CastPermissive(src, distMax, cast)
+}
+
+// FOVDiamond is a diamond-walls field-of-view, see [Map.CastDiamond].
+type FOVDiamond[T any] struct{}
+
+// Compute implements [FOV] interface.
+func (FOVDiamond[T]) Compute(m *Map[T], src image.Point, distMax float64, cast Cast[T]) {
+	m.CastDiamond(src, distMax, cast)
+}
@@ -0,0 +1,179 @@
+package grid
+
+import (
+	"image"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func fovAlgorithms() map[string]FOV[bool] {
+	return map[string]FOV[bool]{
+		"rays":       FOVRays[bool]{},
+		"shadow":     FOVShadow[bool]{},
+		"symmetric":  NewFOVSymmetric(isWall),
+		"permissive": FOVPermissive[bool]{},
+		"diamond":    FOVDiamond[bool]{},
+	}
+}
+
+func TestFOVOpen(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 21, 21
+		dist = 6.0
+	)
+
+	var (
+		src  = image.Pt(10, 10)
+		m    = New[bool](image.Rect(0, 0, W, H))
+		want int
+	)
+
+	m.Iter(func(p image.Point, _ bool) bool {
+		if DistanceEuclidean(src, p) <= dist {
+			want++
+		}
+
+		return true
+	})
+
+	for _, name := range []string{"symmetric", "permissive", "diamond"} {
+		seen := make(set.Unordered[image.Point])
+
+		fovAlgorithms()[name].Compute(m, src, dist, func(p image.Point, d float64, w bool) bool {
+			if !seen.Add(p) {
+				t.Fatalf("%s: %v seen twice", name, p)
+			}
+
+			if d > dist {
+				t.Fatalf("%s: %v too far: %f", name, p, d)
+			}
+
+			return !w
+		})
+
+		if len(seen) != want {
+			t.Fatalf("%s: want: %d got: %d", name, want, len(seen))
+		}
+	}
+}
+
+func TestFOVWalls(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 7, 7
+
+	m := New[bool](image.Rect(0, 0, W, H))
+
+	for y := 0; y < H; y++ {
+		m.Set(image.Pt(3, y), true)
+	}
+
+	for _, name := range []string{"rays", "symmetric", "permissive", "diamond"} {
+		var (
+			fov           = fovAlgorithms()[name]
+			walls, floors int
+		)
+
+		fov.Compute(m, image.Pt(1, 3), 10.0, func(p image.Point, _ float64, w bool) bool {
+			if p.X > 3 {
+				t.Fatalf("%s: %v is visible", name, p)
+			}
+
+			if w {
+				walls++
+			} else {
+				floors++
+			}
+
+			return !w
+		})
+
+		if walls == 0 || floors == 0 {
+			t.Fatalf("%s: walls: %d floors: %d", name, walls, floors)
+		}
+
+		fov.Compute(m, image.Pt(W, H), 10.0, func(p image.Point, _ float64, _ bool) bool {
+			t.Fatalf("%s: %v is visible from out-of-bounds", name, p)
+
+			return true
+		})
+	}
+}
+
+func TestFOVPillar(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 9, 9
+
+	var (
+		m      = New[bool](image.Rect(0, 0, W, H))
+		src    = image.Pt(1, 4)
+		pillar = image.Pt(3, 4)
+		hidden = image.Pt(7, 4)
+	)
+
+	m.Set(pillar, true)
+
+	for name, fov := range fovAlgorithms() {
+		var seen bool
+
+		fov.Compute(m, src, 10.0, func(p image.Point, _ float64, w bool) bool {
+			if p.Eq(hidden) {
+				seen = true
+			}
+
+			return !w
+		})
+
+		if seen {
+			t.Fatalf("%s: %v is visible behind pillar", name, hidden)
+		}
+	}
+}
+
+func TestFOVPermissiveIsSymmetric(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H    = 20, 14
+		dist    = 30.0
+		seeds   = 5
+		density = 0.25
+	)
+
+	for seed := uint64(1); seed <= seeds; seed++ {
+		m := randomWalls(seed, W, H, density)
+		fov := make(map[image.Point]set.Unordered[image.Point])
+
+		m.Iter(func(src image.Point, wall bool) bool {
+			if wall {
+				return true
+			}
+
+			seen := make(set.Unordered[image.Point])
+
+			m.CastPermissive(src, dist, func(p image.Point, _ float64, w bool) bool {
+				if !w {
+					seen.Add(p)
+				}
+
+				return !w
+			})
+
+			fov[src] = seen
+
+			return true
+		})
+
+		for a, seen := range fov {
+			for b := range seen {
+				if !fov[b].Has(a) {
+					t.Fatalf("seed %d: %v sees %v, but not vice versa", seed, a, b)
+				}
+			}
+		}
+	}
+}
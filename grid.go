@@ -10,7 +10,11 @@ import (
 	"github.com/zyedidia/generic/heap"
 )
 
-const one = 1.0
+const (
+	one        = 1.0
+	half       = 0.5
+	maxDegrees = 360.0
+)
 
 // Iter is an iteration callback.
 type Iter[T any] func(image.Point, T) bool
@@ -26,8 +30,9 @@ type Distance func(a, b image.Point) float64
 
 // Map represents generic 2D grid map.
 type Map[T any] struct {
-	cells array2d.Array[T]
-	rc    image.Rectangle
+	cells    array2d.Array[T]
+	watchers []watcher
+	rc       image.Rectangle
 }
 
 // New return empty [Map] with given bounding rectangle.
@@ -66,7 +71,11 @@ func (m *Map[T]) MustGet(p image.Point) (c T) {
 
 // Set sets value at given point.
 func (m *Map[T]) Set(p image.Point, v T) (ok bool) {
-	return m.cells.Set(p.X, p.Y, v)
+	if ok = m.cells.Set(p.X, p.Y, v); ok {
+		m.notify(p)
+	}
+
+	return ok
 }
 
 // Iter iterates over map cells.
@@ -79,6 +88,7 @@ func (m *Map[T]) Iter(it Iter[T]) {
 // Fill fills map with given constructor.
 func (m *Map[T]) Fill(filler func() T) {
 	m.cells.Fill(filler)
+	m.notifyAll()
 }
 
 // Neighbours iterates grid cell neighbours in given directions and order.
@@ -167,24 +177,67 @@ func (m *Map[T]) Path(
 	return nil, false
 }
 
-// LineOfSight iterates visible cells within given distance.
+// ValidatePath re-checks every point of given path against cost function, except the source one (as [Map.Path]
+// does), it reports false if any point is out-of-bounds or not walkable anymore.
+func (m *Map[T]) ValidatePath(
+	points []image.Point,
+	dist Distance,
+	cost Cost[T],
+) (ok bool) {
+	if len(points) == 0 {
+		return false
+	}
+
+	var (
+		dst = points[len(points)-1]
+		val T
+	)
+
+	for i, p := range points {
+		if val, ok = m.cells.Get(p.X, p.Y); !ok {
+			return false
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if _, ok = cost(p, dist(dst, p), val); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LineOfSight iterates visible cells within given distance, every cell is visited exactly once.
+// Rays are cast clockwise starting from east (zero angle), cells on every ray are visited from near to far,
+// number of rays grows with distance, so far cells are not skipped.
 func (m *Map[T]) LineOfSight(
 	src image.Point,
 	distMax float64,
 	cast Cast[T],
 ) {
-	if !src.In(m.rc) {
-		return
-	}
-
-	const maxDegrees = 360.0
+	m.castRays(src, 0, maxDegrees, distMax, cast)
+}
 
-	for t := float64(0); t < maxDegrees; t++ {
-		m.CastRay(src, t, distMax, cast)
+// LineOfSightCone iterates visible cells within given distance and arc of given width (in degrees),
+// centered at facing angle (in degrees). Rays are cast clockwise, see [Map.LineOfSight] for details.
+func (m *Map[T]) LineOfSightCone(
+	src image.Point,
+	facing, width, distMax float64,
+	cast Cast[T],
+) {
+	if width > maxDegrees {
+		width = maxDegrees
 	}
+
+	m.castRays(src, facing-width/2, width, distMax, cast)
 }
 
-// CastRay performs DDA ray cast from point at map with given angle (in degrees), limited by given max distance.
+// CastRay performs DDA ray cast from center of given cell at map with given angle (in degrees), limited by given
+// max distance. Cast is called for every traversed cell (except the source one) with distance from source center
+// to the point, where ray enters the cell.
 func (m *Map[T]) CastRay(
 	src image.Point,
 	angle, distMax float64,
@@ -194,63 +247,11 @@ func (m *Map[T]) CastRay(
 		return
 	}
 
-	var (
-		start      = vec2d.New(float64(src.X), float64(src.Y))
-		s, c       = math.Sincos(radians(angle))
-		dest       = start.Add(vec2d.New(c, s))
-		rdir       = dest.Sub(start).Norm()
-		step, rlen vec2d.V[float64]
-	)
-
-	if rdir.X < 0 {
-		rlen.X = start.X - start.X
-		step.X = -one
-	} else {
-		rlen.X = (start.X + one) - start.X
-		step.X = one
-	}
-
-	if rdir.Y < 0 {
-		rlen.Y = start.Y - start.Y
-		step.Y = -one
-	} else {
-		rlen.Y = (start.Y + one) - start.Y
-		step.Y = one
-	}
-
-	var (
-		unit = vec2d.New(one, one).Div(rdir).Abs()
-		mpt  image.Point
-		dist float64
-		val  T
-		ok   bool
-	)
+	origin := vec2d.New(float64(src.X)+half, float64(src.Y)+half)
 
-	rlen = rlen.Mul(unit)
-
-	for {
-		if rlen.X < rlen.Y {
-			start.X += step.X
-			rlen.X += unit.X
-		} else {
-			start.Y += step.Y
-			rlen.Y += unit.Y
-		}
-
-		if dist = math.Max(rlen.X, rlen.Y); dist > distMax {
-			break
-		}
-
-		mpt = image.Pt(int(start.X), int(start.Y))
-
-		if val, ok = m.cells.Get(mpt.X, mpt.Y); !ok {
-			break
-		}
-
-		if !cast(mpt, dist, val) {
-			break
-		}
-	}
+	m.CastRayFrom(origin, angle, distMax, func(h RayHit, val T) bool {
+		return cast(h.Cell, h.Dist, val)
+	})
 }
 
 // CastShadow performs recursive shadow-casting.
@@ -303,52 +304,18 @@ func (m *Map[T]) LineBresenham(
 		return
 	}
 
-	const two = 2
-
 	var (
-		sx, sy = 1, 1
-		dx, dy = abs(dst.X - src.X), -abs(dst.Y - src.Y)
-		e1     = dx + dy
-		e2     int
-		val    T
-		ok     bool
+		val T
+		ok  bool
 	)
 
-	if src.X > dst.X {
-		sx = -1
-	}
-
-	if src.Y > dst.Y {
-		sy = -1
-	}
-
-	cur := src
-
-	for {
-		if val, ok = m.cells.Get(cur.X, cur.Y); !ok {
-			break
+	bresenham(src, dst, func(p image.Point) bool {
+		if val, ok = m.cells.Get(p.X, p.Y); !ok {
+			return false
 		}
 
-		if !iter(cur, val) {
-			break
-		}
-
-		if cur.Eq(dst) {
-			break
-		}
-
-		e2 = e1 * two
-
-		if e2 >= dy {
-			cur.X += sx
-			e1 += dy
-		}
-
-		if e2 <= dx {
-			cur.Y += sy
-			e1 += dx
-		}
-	}
+		return iter(p, val)
+	})
 }
 
 func (m *Map[T]) emitShadow(
@@ -361,8 +328,6 @@ func (m *Map[T]) emitShadow(
 		return
 	}
 
-	const half = 0.5
-
 	var (
 		pt      image.Point
 		low     = math.Floor(slopeLow*dist + half)
@@ -397,12 +362,88 @@ func (m *Map[T]) emitShadow(
 	m.emitShadow(src, oct, dist+1, distMax, slopeLow, slopeHigh, cast)
 }
 
+func (m *Map[T]) castRays(
+	src image.Point,
+	from, arc, distMax float64,
+	cast Cast[T],
+) {
+	seen := make(map[image.Point]bool)
+
+	visit := func(h *RayHit) (ok bool) {
+		var (
+			v     T
+			found bool
+		)
+
+		if v, ok = m.cells.Get(h.Cell.X, h.Cell.Y); !ok {
+			return false
+		}
+
+		if ok, found = seen[h.Cell]; !found {
+			ok = cast(h.Cell, h.Dist, v)
+			seen[h.Cell] = ok
+		}
+
+		return ok
+	}
+
+	m.fan(src, from, arc, distMax, func() func(*RayHit) bool {
+		return visit
+	})
+}
+
+// fan casts rays from center of source cell within given arc, ray visitor is created for every ray,
+// number of rays grows with distance, so far cells are not skipped.
+func (m *Map[T]) fan(
+	src image.Point,
+	from, arc, distMax float64,
+	ray func() func(*RayHit) bool,
+) {
+	if !src.In(m.rc) || arc <= 0 {
+		return
+	}
+
+	const (
+		minRays     = 360
+		raysPerCell = 2.0
+	)
+
+	var (
+		w, h   = m.cells.Bounds()
+		dist   = math.Min(distMax, math.Hypot(float64(w), float64(h)))
+		rays   = max(int(minRays*arc/maxDegrees), int(math.Ceil(radians(arc)*dist*raysPerCell)))
+		step   = arc / float64(rays)
+		origin = vec2d.New(float64(src.X)+half, float64(src.Y)+half)
+	)
+
+	if arc < maxDegrees {
+		rays++ // include both edges of the arc
+	}
+
+	for i := 0; i < rays; i++ {
+		s, c := math.Sincos(radians(from + float64(i)*step))
+
+		walkRay(origin, vec2d.New(c, s), distMax, ray())
+	}
+}
+
 func radians(v float64) (d float64) {
 	const rad2deg = 180.0 / math.Pi
 
 	return v / rad2deg
 }
 
+func degrees(v float64) (d float64) {
+	const rad2deg = 180.0 / math.Pi
+
+	d = math.Mod(v*rad2deg, maxDegrees)
+	if d < 0 {
+		d += maxDegrees
+	}
+
+	return d
+}
+
 func octantPoint(p image.Point, oct, d, h int) (rv image.Point) {
 	if oct&0x1 > 0 {
 		d = -d
@@ -421,6 +462,48 @@ func octantPoint(p image.Point, oct, d, h int) (rv image.Point) {
 	return p.Add(rv)
 }
 
+func bresenham(
+	src, dst image.Point,
+	fn func(image.Point) bool,
+) {
+	const two = 2
+
+	var (
+		sx, sy = 1, 1
+		dx, dy = abs(dst.X - src.X), -abs(dst.Y - src.Y)
+		e1     = dx + dy
+		e2     int
+	)
+
+	if src.X > dst.X {
+		sx = -1
+	}
+
+	if src.Y > dst.Y {
+		sy = -1
+	}
+
+	cur := src
+
+	for {
+		if !fn(cur) || cur.Eq(dst) {
+			break
+		}
+
+		e2 = e1 * two
+
+		if e2 >= dy {
+			cur.X += sx
+			e1 += dy
+		}
+
+		if e2 <= dx {
+			cur.Y += sy
+			e1 += dx
+		}
+	}
+}
+
 func abs(v int) int {
 	if v < 0 {
 		return -v
@@ -265,6 +265,14 @@ func TestMapPath(t *testing.T) {
 		t.Fatal("step 1 - 2")
 	}
 
+	if !p[0].Eq(src) || !p[len(p)-1].Eq(dst) {
+		t.Fatal("step 1 - 3")
+	}
+
+	if !m.ValidatePath(p, DistanceManhattan, coster) {
+		t.Fatal("step 1 - 4")
+	}
+
 	// step 2: first wall
 	walls.Add(image.Pt(2, 1))
 
@@ -301,6 +309,33 @@ func TestMapPath(t *testing.T) {
 		t.Fatal("step 3 - 2")
 	}
 
+	if m.ValidatePath(p, DistanceManhattan, func(p image.Point, d float64, _ struct{}) (float64, bool) {
+		return d, !p.Eq(image.Pt(1, 2))
+	}) {
+		t.Fatal("step 3 - 3")
+	}
+
+	if m.ValidatePath([]image.Point{src, image.Pt(-1, 1)}, DistanceManhattan, coster) {
+		t.Fatal("step 3 - 4")
+	}
+
+	if m.ValidatePath(nil, DistanceManhattan, coster) {
+		t.Fatal("step 3 - 5")
+	}
+
+	// source cell is not checked by cost, as in Path
+	walls.Add(src)
+
+	if !m.ValidatePath(p, DistanceManhattan, coster) {
+		t.Fatal("step 3 - 6")
+	}
+
+	if m.ValidatePath([]image.Point{image.Pt(-1, 1), src}, DistanceManhattan, coster) {
+		t.Fatal("step 3 - 7")
+	}
+
+	walls.Del(src)
+
 	// step 4: the last diagonal wall
 	walls.Add(image.Pt(1, 3))
 
@@ -395,6 +430,80 @@ func TestMapLOS(t *testing.T) {
 	}
 }
 
+func TestMapLOSLarge(t *testing.T) {
+	t.Parallel()
+
+	for _, dist := range []float64{10.0, 45.0, 90.0, 150.0} {
+		var (
+			side = int(dist)*2 + 1
+			src  = image.Pt(side/2, side/2)
+			m    = New[struct{}](image.Rect(0, 0, side, side))
+			seen = make(set.Unordered[image.Point])
+		)
+
+		m.LineOfSight(src, dist, func(p image.Point, d float64, _ struct{}) bool {
+			if !seen.Add(p) {
+				t.Fatalf("dist %f: %v seen twice", dist, p)
+			}
+
+			if d > dist {
+				t.Fatalf("dist %f: %v too far: %f", dist, p, d)
+			}
+
+			return true
+		})
+
+		m.Iter(func(p image.Point, _ struct{}) bool {
+			if !p.Eq(src) && DistanceEuclidean(src, p) <= dist && !seen.Has(p) {
+				t.Fatalf("dist %f: %v not seen", dist, p)
+			}
+
+			return true
+		})
+	}
+}
+
+func TestMapLOSOrder(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 21, 21
+		dist = 8.0
+	)
+
+	var (
+		src   = image.Pt(10, 10)
+		m     = New[struct{}](image.Rect(0, 0, W, H))
+		order = func() (rv []image.Point) {
+			m.LineOfSight(src, dist, func(p image.Point, _ float64, _ struct{}) bool {
+				rv = append(rv, p)
+
+				return true
+			})
+
+			return rv
+		}
+		a, b = order(), order()
+	)
+
+	if len(a) != len(b) {
+		t.Fatal("length differs")
+	}
+
+	for i := range a {
+		if !a[i].Eq(b[i]) {
+			t.Fatalf("order differs at %d: %v != %v", i, a[i], b[i])
+		}
+	}
+
+	// first ray goes east, from near to far
+	for i := 0; i < int(dist); i++ {
+		if want := src.Add(image.Pt(i+1, 0)); !a[i].Eq(want) {
+			t.Fatalf("step %d want: %v got: %v", i, want, a[i])
+		}
+	}
+}
+
 func TestMapRayOOB(t *testing.T) {
 	t.Parallel()
 
@@ -432,6 +541,70 @@ func TestMapRayOOB(t *testing.T) {
 	}
 }
 
+func TestMapRayDirections(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 9, 9
+		dist = 10.0
+	)
+
+	var (
+		src   = image.Pt(4, 4)
+		m     = New[struct{}](image.Rect(0, 0, W, H))
+		cases = []struct {
+			Angle float64
+			Last  image.Point
+		}{
+			{Angle: 0, Last: image.Pt(8, 4)},
+			{Angle: 90, Last: image.Pt(4, 8)},
+			{Angle: 180, Last: image.Pt(0, 4)},
+			{Angle: 270, Last: image.Pt(4, 0)},
+			{Angle: 225, Last: image.Pt(0, 0)},
+		}
+	)
+
+	for i, tc := range cases {
+		var last image.Point
+
+		m.CastRay(src, tc.Angle, dist, func(p image.Point, _ float64, _ struct{}) bool {
+			last = p
+
+			return true
+		})
+
+		if last != tc.Last {
+			t.Fatalf("case[%d] failed want: %v got: %v", i, tc.Last, last)
+		}
+
+		var steps int
+
+		m.CastRay(src, tc.Angle, dist, func(_ image.Point, _ float64, _ struct{}) bool {
+			steps++
+
+			return false
+		})
+
+		if steps != 1 {
+			t.Fatalf("case[%d] break: %d", i, steps)
+		}
+	}
+}
+
+func TestMapFanEmpty(t *testing.T) {
+	t.Parallel()
+
+	m := New[struct{}](image.Rect(0, 0, 5, 5))
+
+	for _, arc := range []float64{0, -90} {
+		m.fan(image.Pt(2, 2), 0, arc, 5, func() func(*RayHit) bool {
+			t.Fatalf("arc %f", arc)
+
+			return nil
+		})
+	}
+}
+
 func TestMapShadow(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,254 @@
+package grid
+
+import (
+	"image"
+	"math"
+
+	"github.com/s0rg/array2d"
+)
+
+// LightColor is a light color (or level), components are not clamped.
+type LightColor struct {
+	R, G, B float64
+}
+
+// White is a white light color with unit intensity.
+var White = LightColor{R: one, G: one, B: one}
+
+// Add returns sum of two colors.
+func (c LightColor) Add(o LightColor) LightColor {
+	return LightColor{R: c.R + o.R, G: c.G + o.G, B: c.B + o.B}
+}
+
+// Scale returns color, with every component multiplied by given factor.
+func (c LightColor) Scale(f float64) LightColor {
+	return LightColor{R: c.R * f, G: c.G * f, B: c.B * f}
+}
+
+// Level returns brightness of the color, as mean of its components.
+func (c LightColor) Level() float64 {
+	const components = 3
+
+	return (c.R + c.G + c.B) / components
+}
+
+// Falloff returns light intensity factor for given distance from light source with given radius.
+// All provided falloffs return zero for non-positive radius.
+type Falloff func(dist, radius float64) float64
+
+// FalloffNone keeps full intensity within light radius.
+func FalloffNone(_, radius float64) float64 {
+	if radius <= 0 {
+		return 0
+	}
+
+	return one
+}
+
+// FalloffLinear decreases intensity linearly, reaching zero at light radius.
+func FalloffLinear(dist, radius float64) float64 {
+	if radius <= 0 {
+		return 0
+	}
+
+	return math.Max(0, one-dist/radius)
+}
+
+// FalloffQuadratic decreases intensity quadratically, reaching zero at light radius.
+func FalloffQuadratic(dist, radius float64) float64 {
+	f := FalloffLinear(dist, radius)
+
+	return f * f
+}
+
+// FalloffInverseSquare decreases intensity with inverse square of distance.
+func FalloffInverseSquare(dist, radius float64) float64 {
+	if radius <= 0 {
+		return 0
+	}
+
+	return one / (one + dist*dist)
+}
+
+// Light is a light source.
+type Light struct {
+	Falloff   Falloff // FalloffLinear is used, if empty
+	Color     LightColor
+	Pos       image.Point
+	Radius    float64 // light with non-positive radius emits no light
+	Intensity float64
+}
+
+type litCell struct {
+	Color LightColor
+	Point image.Point
+}
+
+type lightState struct {
+	cells []litCell
+	Light
+	dirty bool
+}
+
+// Lighting calculates per-cell light levels for multiple light sources, using symmetric shadow-casting.
+// Changes of lights and map cells (made via [Map.Set] or [Map.Fill]) are applied on [Lighting.Update] call.
+type Lighting[T any] struct {
+	m      *Map[T]
+	opaque Iter[T]
+	lights map[int]*lightState
+	levels array2d.Array[LightColor]
+	nextID int
+}
+
+// NewLighting creates empty [Lighting] for given map, opaque reports cells, that blocks the light.
+func NewLighting[T any](m *Map[T], opaque Iter[T]) (rv *Lighting[T]) {
+	rv = &Lighting[T]{
+		m:      m,
+		opaque: opaque,
+		lights: make(map[int]*lightState),
+		levels: array2d.New[LightColor](m.cells.Bounds()),
+	}
+
+	m.watch(rv)
+
+	return rv
+}
+
+// Add adds new light source, it returns light id.
+func (l *Lighting[T]) Add(light Light) (id int) {
+	id = l.nextID
+	l.nextID++
+
+	l.lights[id] = &lightState{Light: light, dirty: true}
+
+	return id
+}
+
+// Get returns light source by its id.
+func (l *Lighting[T]) Get(id int) (light Light, ok bool) {
+	s, ok := l.lights[id]
+	if !ok {
+		return light, false
+	}
+
+	return s.Light, true
+}
+
+// Set replaces light source with given id.
+func (l *Lighting[T]) Set(id int, light Light) (ok bool) {
+	s, ok := l.lights[id]
+	if !ok {
+		return false
+	}
+
+	s.Light, s.dirty = light, true
+
+	return true
+}
+
+// Move moves light source with given id to the new position.
+func (l *Lighting[T]) Move(id int, pos image.Point) (ok bool) {
+	s, ok := l.lights[id]
+	if !ok {
+		return false
+	}
+
+	s.Pos, s.dirty = pos, true
+
+	return true
+}
+
+// Remove removes light source with given id, its light is removed immediately.
+func (l *Lighting[T]) Remove(id int) {
+	s, ok := l.lights[id]
+	if !ok {
+		return
+	}
+
+	l.unlit(s)
+
+	delete(l.lights, id)
+}
+
+// Update recalculates light levels for all changed light sources.
+func (l *Lighting[T]) Update() {
+	for _, s := range l.lights {
+		if !s.dirty {
+			continue
+		}
+
+		l.unlit(s)
+		l.lit(s)
+
+		s.dirty = false
+	}
+}
+
+// Level returns light level at given point.
+func (l *Lighting[T]) Level(p image.Point) (c LightColor, ok bool) {
+	return l.levels.Get(p.X, p.Y)
+}
+
+// Iter iterates over light levels for all map cells.
+func (l *Lighting[T]) Iter(it func(image.Point, LightColor) bool) {
+	l.levels.Iter(func(x, y int, c LightColor) (next bool) {
+		return it(image.Pt(x, y), c)
+	})
+}
+
+// Close detaches lighting from its map.
+func (l *Lighting[T]) Close() {
+	l.m.unwatch(l)
+}
+
+func (l *Lighting[T]) lit(s *lightState) {
+	if s.Radius <= 0 {
+		return
+	}
+
+	var (
+		base    = s.Color.Scale(s.Intensity)
+		falloff = s.Falloff
+	)
+
+	if falloff == nil {
+		falloff = FalloffLinear
+	}
+
+	l.m.CastShadowSymmetric(s.Pos, s.Radius, l.opaque, func(p image.Point, dist float64, _ T) bool {
+		c := base.Scale(falloff(dist, s.Radius))
+
+		s.cells = append(s.cells, litCell{Point: p, Color: c})
+		l.add(p, c)
+
+		return true
+	})
+}
+
+func (l *Lighting[T]) unlit(s *lightState) {
+	for _, c := range s.cells {
+		l.add(c.Point, c.Color.Scale(-one))
+	}
+
+	s.cells = s.cells[:0]
+}
+
+func (l *Lighting[T]) add(p image.Point, c LightColor) {
+	v, _ := l.levels.Get(p.X, p.Y)
+
+	l.levels.Set(p.X, p.Y, v.Add(c))
+}
+
+func (l *Lighting[T]) reset() {
+	for _, s := range l.lights {
+		s.dirty = true
+	}
+}
+
+func (l *Lighting[T]) changed(p image.Point) {
+	for _, s := range l.lights {
+		if DistanceEuclidean(s.Pos, p) <= s.Radius {
+			s.dirty = true
+		}
+	}
+}
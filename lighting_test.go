@@ -0,0 +1,221 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+const lightEps = 1e-9
+
+func lightLevel(t *testing.T, l *Lighting[bool], p image.Point) float64 {
+	t.Helper()
+
+	c, ok := l.Level(p)
+	if !ok {
+		t.Fatalf("no level at %v", p)
+	}
+
+	return c.Level()
+}
+
+func TestLighting(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 21, 11
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		l   = NewLighting(m, isWall)
+		src = image.Pt(5, 5)
+	)
+
+	id := l.Add(Light{Pos: src, Radius: 4, Intensity: 2, Color: White})
+	l.Update()
+
+	if v := lightLevel(t, l, src); math.Abs(v-2) > lightEps {
+		t.Fatalf("source level: %f", v)
+	}
+
+	if v := lightLevel(t, l, image.Pt(7, 5)); math.Abs(v-1) > lightEps {
+		t.Fatalf("half level: %f", v)
+	}
+
+	if v := lightLevel(t, l, image.Pt(10, 5)); v != 0 {
+		t.Fatalf("far level: %f", v)
+	}
+
+	// second light adds up
+	id2 := l.Add(Light{Pos: image.Pt(9, 5), Radius: 4, Intensity: 2, Color: LightColor{R: 1}, Falloff: FalloffNone})
+	l.Update()
+
+	if c, _ := l.Level(image.Pt(7, 5)); math.Abs(c.R-3) > lightEps || math.Abs(c.G-1) > lightEps {
+		t.Fatalf("mixed level: %+v", c)
+	}
+
+	// move first light away
+	if !l.Move(id, image.Pt(15, 5)) {
+		t.Fatal("move")
+	}
+
+	l.Update()
+
+	if c, _ := l.Level(src); c.G > lightEps || math.Abs(c.R-2) > lightEps {
+		t.Fatalf("moved level: %+v", c)
+	}
+
+	// walls cast shadows
+	for y := 0; y < H; y++ {
+		m.Set(image.Pt(12, y), true)
+	}
+
+	l.Update()
+
+	if c, _ := l.Level(image.Pt(11, 5)); c.G > lightEps {
+		t.Fatalf("shadow level: %+v", c)
+	}
+
+	if v := lightLevel(t, l, image.Pt(12, 5)); v <= 0 {
+		t.Fatalf("wall level: %f", v)
+	}
+
+	// change light
+	light, ok := l.Get(id2)
+	if !ok {
+		t.Fatal("get")
+	}
+
+	light.Intensity = 0
+
+	if !l.Set(id2, light) {
+		t.Fatal("set")
+	}
+
+	l.Update()
+
+	if v := lightLevel(t, l, image.Pt(9, 5)); v > lightEps {
+		t.Fatalf("zero intensity level: %f", v)
+	}
+
+	l.Remove(id)
+	l.Remove(id)
+
+	var total float64
+
+	l.Iter(func(_ image.Point, c LightColor) bool {
+		total += c.Level()
+
+		return true
+	})
+
+	if total > lightEps {
+		t.Fatalf("total level: %f", total)
+	}
+
+	if _, ok = l.Get(id); ok {
+		t.Fail()
+	}
+
+	if l.Set(id, light) || l.Move(id, src) {
+		t.Fail()
+	}
+
+	l.Close()
+
+	if _, ok = l.Level(image.Pt(W, H)); ok {
+		t.Fail()
+	}
+}
+
+func TestLightingFalloff(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		Falloff    Falloff
+		Dist, Want float64
+	}{
+		{Falloff: FalloffNone, Dist: 3, Want: 1},
+		{Falloff: FalloffLinear, Dist: 3, Want: 0.25},
+		{Falloff: FalloffLinear, Dist: 5, Want: 0},
+		{Falloff: FalloffQuadratic, Dist: 2, Want: 0.25},
+		{Falloff: FalloffInverseSquare, Dist: 1, Want: 0.5},
+	}
+
+	for i, tc := range cases {
+		if v := tc.Falloff(tc.Dist, 4); math.Abs(v-tc.Want) > lightEps {
+			t.Fatalf("case[%d] failed want: %f got: %f", i, tc.Want, v)
+		}
+
+		if v := tc.Falloff(0, 0); v != 0 {
+			t.Fatalf("case[%d] zero radius: %f", i, v)
+		}
+	}
+}
+
+func TestLightingZeroRadius(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 9, 9
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		l   = NewLighting(m, isWall)
+		src = image.Pt(4, 4)
+	)
+
+	id := l.Add(Light{Pos: src, Intensity: 1, Color: White})
+	l.Update()
+
+	if v := lightLevel(t, l, src); v != 0 {
+		t.Fatalf("zero radius: %f", v)
+	}
+
+	l.Set(id, Light{Pos: src, Radius: 4, Intensity: 1, Color: White})
+	l.Update()
+
+	if v := lightLevel(t, l, src); math.IsNaN(v) || math.Abs(v-1) > lightEps {
+		t.Fatalf("valid radius: %f", v)
+	}
+
+	l.Set(id, Light{Pos: src, Radius: -1, Intensity: 1, Color: White, Falloff: FalloffNone})
+	l.Update()
+
+	l.Iter(func(p image.Point, c LightColor) bool {
+		if v := c.Level(); math.IsNaN(v) || math.Abs(v) > lightEps {
+			t.Fatalf("negative radius at %v: %f", p, v)
+		}
+
+		return true
+	})
+}
+
+func TestLightingFill(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 9, 9
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		l   = NewLighting(m, isWall)
+		src = image.Pt(4, 4)
+	)
+
+	l.Add(Light{Pos: src, Radius: 4, Intensity: 1, Color: White})
+	l.Update()
+
+	if v := lightLevel(t, l, image.Pt(4, 1)); v <= 0 {
+		t.Fatalf("step 1: %f", v)
+	}
+
+	m.Fill(func() bool { return true })
+	l.Update()
+
+	// walls around the source are lit, cells behind them are not
+	if v := lightLevel(t, l, image.Pt(4, 3)); v <= 0 {
+		t.Fatalf("step 2 - wall: %f", v)
+	}
+
+	if v := lightLevel(t, l, image.Pt(4, 1)); v != 0 {
+		t.Fatalf("step 2 - stale: %f", v)
+	}
+}
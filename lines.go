@@ -0,0 +1,173 @@
+package grid
+
+import (
+	"image"
+	"slices"
+)
+
+// Coverage is a weighted iteration callback, it receives cell coverage (in range (0, 1]).
+type Coverage[T any] func(image.Point, float64, T) bool
+
+// LineSupercover iterates every cell, touched by the line between centers of given cells, so line never
+// passes diagonally between two cells. If line passes exactly trough cells corner, both side cells are visited
+// before the diagonal one (horizontal neighbour goes first).
+func (m *Map[T]) LineSupercover(
+	src, dst image.Point,
+	iter Iter[T],
+) {
+	const two = 2
+
+	if !src.In(m.rc) {
+		return
+	}
+
+	var (
+		nx, ny = abs(dst.X - src.X), abs(dst.Y - src.Y)
+		sx, sy = sign(dst.X - src.X), sign(dst.Y - src.Y)
+		cur    = src
+		visit  = func(p image.Point) (ok bool) {
+			var val T
+
+			if val, ok = m.cells.Get(p.X, p.Y); !ok {
+				return false
+			}
+
+			return iter(p, val)
+		}
+	)
+
+	if !visit(cur) {
+		return
+	}
+
+	for ix, iy := 0, 0; ix < nx || iy < ny; {
+		switch decision := (1+two*ix)*ny - (1+two*iy)*nx; {
+		case decision == 0: // exact corner
+			if !visit(image.Pt(cur.X+sx, cur.Y)) || !visit(image.Pt(cur.X, cur.Y+sy)) {
+				return
+			}
+
+			cur.X, cur.Y = cur.X+sx, cur.Y+sy
+			ix, iy = ix+1, iy+1
+		case decision < 0:
+			cur.X += sx
+			ix++
+		default:
+			cur.Y += sy
+			iy++
+		}
+
+		if !visit(cur) {
+			return
+		}
+	}
+}
+
+// LineSymmetric is line by Bresenham's algorithm, that always visits the same cells for both
+// src -> dst and dst -> src directions.
+func (m *Map[T]) LineSymmetric(
+	src, dst image.Point,
+	iter Iter[T],
+) {
+	if !src.In(m.rc) {
+		return
+	}
+
+	var (
+		points  []image.Point
+		reverse = src.X > dst.X || (src.X == dst.X && src.Y > dst.Y)
+		from    = src
+		to      = dst
+	)
+
+	if reverse {
+		from, to = to, from
+	}
+
+	bresenham(from, to, func(p image.Point) bool {
+		points = append(points, p)
+
+		return true
+	})
+
+	if reverse {
+		slices.Reverse(points)
+	}
+
+	var (
+		val T
+		ok  bool
+	)
+
+	for _, p := range points {
+		if val, ok = m.cells.Get(p.X, p.Y); !ok {
+			break
+		}
+
+		if !iter(p, val) {
+			break
+		}
+	}
+}
+
+// LineWu is anti-aliased line by Xiaolin Wu's algorithm, cells are reported with their coverage.
+// Along the major axis, cells are visited from src to dst, out-of-bounds cells are skipped.
+func (m *Map[T]) LineWu(
+	src, dst image.Point,
+	cover Coverage[T],
+) {
+	if !src.In(m.rc) {
+		return
+	}
+
+	var (
+		steep  = abs(dst.Y-src.Y) > abs(dst.X-src.X)
+		x0, y0 = src.X, src.Y
+		x1, y1 = dst.X, dst.Y
+	)
+
+	if steep {
+		x0, y0, x1, y1 = y0, x0, y1, x1
+	}
+
+	var (
+		step   = sign(x1 - x0)
+		dx, dy = max(abs(x1-x0), 1), y1 - y0
+	)
+
+	plot := func(x, y int, w float64) bool {
+		if steep {
+			x, y = y, x
+		}
+
+		val, ok := m.cells.Get(x, y)
+		if !ok || w <= 0 {
+			return true
+		}
+
+		return cover(image.Pt(x, y), w, val)
+	}
+
+	// exact minor axis position at i-th step is: y0 + dy * i / dx
+	for i, x := 0, x0; ; i, x = i+1, x+step {
+		var (
+			y    = y0 + floorDiv(dy*i, dx)
+			frac = float64(dy*i-floorDiv(dy*i, dx)*dx) / float64(dx)
+		)
+
+		if !plot(x, y, one-frac) || !plot(x, y+1, frac) || x == x1 {
+			break
+		}
+	}
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,237 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/s0rg/vec2d"
+)
+
+func collectLine(line func(src, dst image.Point, iter Iter[struct{}]), src, dst image.Point) (rv []image.Point) {
+	line(src, dst, func(p image.Point, _ struct{}) bool {
+		rv = append(rv, p)
+
+		return true
+	})
+
+	return rv
+}
+
+func TestLineSupercover(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 9, 9
+		eps  = 1e-9
+	)
+
+	var (
+		m   = New[struct{}](image.Rect(0, 0, W, H))
+		src = image.Pt(4, 4)
+	)
+
+	want := []image.Point{
+		image.Pt(1, 1), image.Pt(2, 1), image.Pt(1, 2), image.Pt(2, 2),
+		image.Pt(3, 2), image.Pt(2, 3), image.Pt(3, 3),
+	}
+
+	if got := collectLine(m.LineSupercover, image.Pt(1, 1), image.Pt(3, 3)); !slices.Equal(got, want) {
+		t.Fatal("diagonal", got)
+	}
+
+	m.Iter(func(dst image.Point, _ struct{}) bool {
+		var (
+			line   = collectLine(m.LineSupercover, src, dst)
+			origin = vec2d.New(float64(src.X)+half, float64(src.Y)+half)
+			delta  = vec2d.New(float64(dst.X-src.X), float64(dst.Y-src.Y))
+			length = delta.Len()
+			dir    = vec2d.New(one, 0)
+		)
+
+		if length > 0 {
+			dir = delta.DivScalar(length)
+		}
+
+		if !line[0].Eq(src) || !line[len(line)-1].Eq(dst) {
+			t.Fatalf("%v: invalid endpoints: %v", dst, line)
+		}
+
+		for i, p := range line {
+			if segmentOverlap(origin, dir, length, p) < -eps {
+				t.Fatalf("%v: %v is not on the line", dst, p)
+			}
+
+			if i > 0 && abs(p.X-line[i-1].X)+abs(p.Y-line[i-1].Y) > 2 {
+				t.Fatalf("%v: %v is not adjacent", dst, p)
+			}
+		}
+
+		m.Iter(func(p image.Point, _ struct{}) bool {
+			if segmentOverlap(origin, dir, length, p) >= -eps && !slices.Contains(line, p) {
+				t.Fatalf("%v: %v is not visited", dst, p)
+			}
+
+			return true
+		})
+
+		return true
+	})
+
+	if l := collectLine(m.LineSupercover, image.Pt(-1, -1), src); len(l) != 0 {
+		t.Fatal("out-of-bounds source")
+	}
+
+	if l := collectLine(m.LineSupercover, src, image.Pt(4, 20)); len(l) != H-4 {
+		t.Fatal("out-of-bounds destination", l)
+	}
+
+	for _, limit := range []int{1, 2, 3} {
+		var count int
+
+		m.LineSupercover(image.Pt(1, 1), image.Pt(3, 3), func(_ image.Point, _ struct{}) bool {
+			count++
+
+			return count < limit
+		})
+
+		if count != limit {
+			t.Fatalf("break at %d: %d", limit, count)
+		}
+	}
+}
+
+func TestLineSymmetric(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 9, 7
+
+	m := New[struct{}](image.Rect(0, 0, W, H))
+
+	m.Iter(func(a image.Point, _ struct{}) bool {
+		m.Iter(func(b image.Point, _ struct{}) bool {
+			fwd, bwd := collectLine(m.LineSymmetric, a, b), collectLine(m.LineSymmetric, b, a)
+
+			slices.Reverse(bwd)
+
+			if !slices.Equal(fwd, bwd) {
+				t.Fatalf("%v -> %v not symmetric: %v %v", a, b, fwd, bwd)
+			}
+
+			if !fwd[0].Eq(a) || !fwd[len(fwd)-1].Eq(b) {
+				t.Fatalf("%v -> %v: invalid endpoints: %v", a, b, fwd)
+			}
+
+			return true
+		})
+
+		return true
+	})
+
+	if l := collectLine(m.LineSymmetric, image.Pt(-1, -1), image.Pt(1, 1)); len(l) != 0 {
+		t.Fatal("out-of-bounds source")
+	}
+
+	if l := collectLine(m.LineSymmetric, image.Pt(1, 1), image.Pt(1, 20)); len(l) != H-1 {
+		t.Fatal("out-of-bounds destination", l)
+	}
+
+	var count int
+
+	m.LineSymmetric(image.Pt(1, 1), image.Pt(5, 5), func(_ image.Point, _ struct{}) bool {
+		count++
+
+		return false
+	})
+
+	if count != 1 {
+		t.Fail()
+	}
+}
+
+func TestLineWu(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 9, 9
+		eps  = 1e-9
+	)
+
+	m := New[struct{}](image.Rect(0, 0, W, H))
+
+	var cases = []struct {
+		Src, Dst image.Point
+		Cells    int
+		Clipped  bool
+	}{
+		{Src: image.Pt(0, 4), Dst: image.Pt(8, 4), Cells: 9},
+		{Src: image.Pt(8, 8), Dst: image.Pt(0, 0), Cells: 9},
+		{Src: image.Pt(0, 0), Dst: image.Pt(8, 2), Cells: 9 + 6},
+		{Src: image.Pt(3, 8), Dst: image.Pt(1, 0), Cells: 9 + 6},
+		{Src: image.Pt(4, 4), Dst: image.Pt(4, 4), Cells: 1},
+		{Src: image.Pt(0, 8), Dst: image.Pt(8, 9), Cells: 8, Clipped: true},
+	}
+
+	for i, tc := range cases {
+		var (
+			sums  = make(map[int]float64)
+			cells int
+			first image.Point
+		)
+
+		steep := abs(tc.Dst.Y-tc.Src.Y) > abs(tc.Dst.X-tc.Src.X)
+
+		m.LineWu(tc.Src, tc.Dst, func(p image.Point, w float64, _ struct{}) bool {
+			if cells == 0 {
+				first = p
+			}
+
+			if w <= 0 || w > 1 {
+				t.Fatalf("case[%d] %v invalid weight: %f", i, p, w)
+			}
+
+			if steep {
+				sums[p.Y] += w
+			} else {
+				sums[p.X] += w
+			}
+
+			cells++
+
+			return true
+		})
+
+		if cells != tc.Cells || !first.Eq(tc.Src) {
+			t.Fatalf("case[%d] cells want: %d got: %d (first: %v)", i, tc.Cells, cells, first)
+		}
+
+		if tc.Clipped {
+			continue
+		}
+
+		for k, s := range sums {
+			if math.Abs(s-1) > eps {
+				t.Fatalf("case[%d] sum at %d: %f", i, k, s)
+			}
+		}
+	}
+
+	var count int
+
+	m.LineWu(image.Pt(0, 0), image.Pt(8, 3), func(_ image.Point, _ float64, _ struct{}) bool {
+		count++
+
+		return count < 2
+	})
+
+	if count != 2 {
+		t.Fail()
+	}
+
+	m.LineWu(image.Pt(-1, 0), image.Pt(8, 3), func(_ image.Point, _ float64, _ struct{}) bool {
+		t.Fatal("out-of-bounds source")
+
+		return true
+	})
+}
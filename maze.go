@@ -0,0 +1,304 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+)
+
+// MazeAlgorithm selects algorithm for [Map.GenerateMaze].
+type MazeAlgorithm uint8
+
+const (
+	// MazeBacktracker is a recursive backtracker (randomized depth-first search), it gives long winding passages.
+	MazeBacktracker MazeAlgorithm = iota
+	// MazePrim is a randomized Prim's algorithm, it gives many short dead ends.
+	MazePrim
+	// MazeKruskal is a randomized Kruskal's algorithm.
+	MazeKruskal
+	// MazeWilson is a Wilson's algorithm (loop-erased random walks), it gives uniform spanning trees.
+	MazeWilson
+)
+
+// MazeConfig holds settings for [Map.GenerateMaze].
+type MazeConfig struct {
+	// Algorithm selects maze algorithm.
+	Algorithm MazeAlgorithm
+	// Braid is a fraction (in range [0, 1]) of dead ends to remove, maze is perfect (has no loops) for zero.
+	Braid float64
+}
+
+// Maze is a result of maze generation.
+type Maze struct {
+	// Cells holds maze nodes, (odd) map cells in row-major order, passages between them are carved too.
+	Cells []image.Point
+	// Links holds indices of cells (in ascending order), that every cell is connected to.
+	Links [][]int
+}
+
+type mazeGen struct {
+	rng   *rand.Rand
+	maze  *Maze
+	w, h  int
+	steps []image.Point
+}
+
+// GenerateMaze fills map with walls, then carves maze with nodes at odd cells, using cardinal directions
+// (see [DirectionsCardinal]).
+func (m *Map[T]) GenerateMaze(
+	src rand.Source,
+	cfg MazeConfig,
+	wall, floor func() T,
+) (rv *Maze) {
+	const two = 2
+
+	var (
+		mw, mh = m.cells.Bounds()
+		gen    = &mazeGen{
+			rng:   rand.New(src),
+			maze:  &Maze{},
+			w:     max(mw-1, 0) / two,
+			h:     max(mh-1, 0) / two,
+			steps: Points(DirectionsCardinal...),
+		}
+	)
+
+	m.Fill(wall)
+
+	if gen.w == 0 || gen.h == 0 {
+		return gen.maze
+	}
+
+	for y := 0; y < gen.h; y++ {
+		for x := 0; x < gen.w; x++ {
+			gen.maze.Cells = append(gen.maze.Cells, image.Pt(two*x+1, two*y+1))
+			gen.maze.Links = append(gen.maze.Links, nil)
+		}
+	}
+
+	switch cfg.Algorithm {
+	case MazePrim:
+		gen.prim()
+	case MazeKruskal:
+		gen.kruskal()
+	case MazeWilson:
+		gen.wilson()
+	default:
+		gen.backtracker()
+	}
+
+	gen.braid(cfg.Braid)
+
+	for i, p := range gen.maze.Cells {
+		m.Set(p, floor())
+
+		for _, j := range gen.maze.Links[i] {
+			m.Set(p.Add(gen.maze.Cells[j]).Div(two), floor())
+		}
+
+		slices.Sort(gen.maze.Links[i])
+	}
+
+	return gen.maze
+}
+
+// neighbours returns indices of nodes next to given one.
+func (g *mazeGen) neighbours(i int) (rv []int) {
+	x, y := i%g.w, i/g.w
+
+	for _, d := range g.steps {
+		if nx, ny := x+d.X, y+d.Y; nx >= 0 && nx < g.w && ny >= 0 && ny < g.h {
+			rv = append(rv, ny*g.w+nx)
+		}
+	}
+
+	return rv
+}
+
+func (g *mazeGen) link(a, b int) {
+	g.maze.Links[a] = append(g.maze.Links[a], b)
+	g.maze.Links[b] = append(g.maze.Links[b], a)
+}
+
+func (g *mazeGen) backtracker() {
+	var (
+		seen  = make([]bool, g.w*g.h)
+		start = g.rng.IntN(len(seen))
+		stack = []int{start}
+	)
+
+	seen[start] = true
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+
+		var next []int
+
+		for _, n := range g.neighbours(cur) {
+			if !seen[n] {
+				next = append(next, n)
+			}
+		}
+
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+
+			continue
+		}
+
+		n := next[g.rng.IntN(len(next))]
+		seen[n] = true
+		g.link(cur, n)
+		stack = append(stack, n)
+	}
+}
+
+func (g *mazeGen) prim() {
+	var (
+		in       = make([]bool, g.w*g.h)
+		frontier = make([]bool, g.w*g.h)
+		front    []int
+		start    = g.rng.IntN(len(in))
+	)
+
+	add := func(i int) {
+		in[i] = true
+
+		for _, n := range g.neighbours(i) {
+			if !in[n] && !frontier[n] {
+				frontier[n] = true
+				front = append(front, n)
+			}
+		}
+	}
+
+	add(start)
+
+	for len(front) > 0 {
+		k := g.rng.IntN(len(front))
+		cur := front[k]
+		front[k] = front[len(front)-1]
+		front = front[:len(front)-1]
+
+		var owners []int
+
+		for _, n := range g.neighbours(cur) {
+			if in[n] {
+				owners = append(owners, n)
+			}
+		}
+
+		g.link(cur, owners[g.rng.IntN(len(owners))])
+		add(cur)
+	}
+}
+
+func (g *mazeGen) kruskal() {
+	var (
+		sets  = make([]int, g.w*g.h)
+		edges [][2]int
+	)
+
+	for i := range sets {
+		sets[i] = i
+
+		for _, n := range g.neighbours(i) {
+			if n > i {
+				edges = append(edges, [2]int{i, n})
+			}
+		}
+	}
+
+	find := func(i int) int {
+		for sets[i] != i {
+			sets[i] = sets[sets[i]]
+			i = sets[i]
+		}
+
+		return i
+	}
+
+	g.rng.Shuffle(len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+
+	for _, e := range edges {
+		if a, b := find(e[0]), find(e[1]); a != b {
+			sets[a] = b
+			g.link(e[0], e[1])
+		}
+	}
+}
+
+func (g *mazeGen) wilson() {
+	var (
+		in   = make([]bool, g.w*g.h)
+		next = make([]int, g.w*g.h)
+	)
+
+	in[g.rng.IntN(len(in))] = true
+
+	for start := range in {
+		if in[start] {
+			continue
+		}
+
+		// random walk, remembering last exit from every node, erases loops
+		for cur := start; !in[cur]; cur = next[cur] {
+			ns := g.neighbours(cur)
+			next[cur] = ns[g.rng.IntN(len(ns))]
+		}
+
+		for cur := start; !in[cur]; cur = next[cur] {
+			in[cur] = true
+			g.link(cur, next[cur])
+		}
+	}
+}
+
+// braid removes given fraction of dead ends, by linking them to neighbours, dead ends are preferred.
+func (g *mazeGen) braid(fraction float64) {
+	if fraction <= 0 {
+		return
+	}
+
+	var ends []int
+
+	for i, links := range g.maze.Links {
+		if len(links) == 1 {
+			ends = append(ends, i)
+		}
+	}
+
+	g.rng.Shuffle(len(ends), func(i, j int) {
+		ends[i], ends[j] = ends[j], ends[i]
+	})
+
+	for _, i := range ends[:int(float64(len(ends))*min(fraction, 1))] {
+		if len(g.maze.Links[i]) != 1 {
+			continue // already joined by other dead end
+		}
+
+		var free, dead []int
+
+		for _, n := range g.neighbours(i) {
+			if slices.Contains(g.maze.Links[i], n) {
+				continue
+			}
+
+			free = append(free, n)
+
+			if len(g.maze.Links[n]) == 1 {
+				dead = append(dead, n)
+			}
+		}
+
+		if len(dead) > 0 {
+			free = dead
+		}
+
+		if len(free) > 0 {
+			g.link(i, free[g.rng.IntN(len(free))])
+		}
+	}
+}
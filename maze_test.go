@@ -0,0 +1,152 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+var mazeAlgorithms = []MazeAlgorithm{
+	MazeBacktracker,
+	MazePrim,
+	MazeKruskal,
+	MazeWilson,
+}
+
+func deadEnds(mz *Maze) (rv int) {
+	for _, links := range mz.Links {
+		if len(links) == 1 {
+			rv++
+		}
+	}
+
+	return rv
+}
+
+func checkMaze(t *testing.T, m *Map[bool], mz *Maze) (edges int) {
+	t.Helper()
+
+	var floors int
+
+	for i, p := range mz.Cells {
+		if m.MustGet(p) || p.X%2 != 1 || p.Y%2 != 1 {
+			t.Fatalf("cell %v", p)
+		}
+
+		if !slices.IsSorted(mz.Links[i]) {
+			t.Fatalf("links %v", mz.Links[i])
+		}
+
+		for _, j := range mz.Links[i] {
+			if DistanceManhattan(p, mz.Cells[j]) != 2 || !slices.Contains(mz.Links[j], i) {
+				t.Fatalf("link %v - %v", p, mz.Cells[j])
+			}
+
+			if m.MustGet(p.Add(mz.Cells[j]).Div(2)) {
+				t.Fatalf("link %v - %v not carved", p, mz.Cells[j])
+			}
+		}
+
+		edges += len(mz.Links[i])
+	}
+
+	m.Iter(func(_ image.Point, wall bool) bool {
+		if !wall {
+			floors++
+		}
+
+		return true
+	})
+
+	if edges /= 2; floors != len(mz.Cells)+edges || !linksConnected(mz.Links) {
+		t.Fatalf("floors %d cells %d edges %d", floors, len(mz.Cells), edges)
+	}
+
+	return edges
+}
+
+func TestMapGenerateMaze(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 31, 21
+
+	for _, algo := range mazeAlgorithms {
+		for seed := uint64(1); seed <= 3; seed++ {
+			var (
+				m  = New[bool](image.Rect(0, 0, W, H))
+				mz = m.GenerateMaze(rand.NewPCG(seed, seed), MazeConfig{Algorithm: algo}, isTrue, isFalse)
+			)
+
+			if len(mz.Cells) != (W/2)*(H/2) {
+				t.Fatalf("algo %d: cells %d", algo, len(mz.Cells))
+			}
+
+			if edges := checkMaze(t, m, mz); edges != len(mz.Cells)-1 {
+				t.Fatalf("algo %d: not perfect, edges %d", algo, edges)
+			}
+
+			var (
+				o    = New[bool](image.Rect(0, 0, W, H))
+				same = o.GenerateMaze(rand.NewPCG(seed, seed), MazeConfig{Algorithm: algo}, isTrue, isFalse)
+			)
+
+			for i := range mz.Links {
+				if !slices.Equal(mz.Links[i], same.Links[i]) {
+					t.Fatalf("algo %d: not reproducible", algo)
+				}
+			}
+		}
+	}
+}
+
+func TestMapGenerateMazeBraid(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 31, 21
+
+	for _, algo := range mazeAlgorithms {
+		var (
+			perfect = New[bool](image.Rect(0, 0, W, H))
+			half    = New[bool](image.Rect(0, 0, W, H))
+			full    = New[bool](image.Rect(0, 0, W, H))
+			mp      = perfect.GenerateMaze(rand.NewPCG(1, 1), MazeConfig{Algorithm: algo}, isTrue, isFalse)
+			mh      = half.GenerateMaze(rand.NewPCG(1, 1), MazeConfig{Algorithm: algo, Braid: 0.5}, isTrue, isFalse)
+			mf      = full.GenerateMaze(rand.NewPCG(1, 1), MazeConfig{Algorithm: algo, Braid: 2}, isTrue, isFalse)
+		)
+
+		checkMaze(t, half, mh)
+		checkMaze(t, full, mf)
+
+		if dp, dh := deadEnds(mp), deadEnds(mh); dh == 0 || dh >= dp || deadEnds(mf) != 0 {
+			t.Fatalf("algo %d: dead ends %d %d %d", algo, dp, dh, deadEnds(mf))
+		}
+	}
+}
+
+func TestMapGenerateMazeSmall(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		Rect  image.Rectangle
+		Cells int
+		Ends  int
+	}{
+		{Rect: image.Rect(0, 0, 2, 9), Cells: 0},
+		{Rect: image.Rect(0, 0, 3, 3), Cells: 1},
+		{Rect: image.Rect(0, 0, 4, 9), Cells: 4, Ends: 2},
+	}
+
+	for i, tc := range cases {
+		for _, algo := range mazeAlgorithms {
+			var (
+				m  = New[bool](tc.Rect)
+				mz = m.GenerateMaze(rand.NewPCG(1, 1), MazeConfig{Algorithm: algo, Braid: 1}, isTrue, isFalse)
+			)
+
+			if len(mz.Cells) != tc.Cells || deadEnds(mz) != tc.Ends {
+				t.Fatalf("case[%d] algo %d: cells %d ends %d", i, algo, len(mz.Cells), deadEnds(mz))
+			}
+		}
+	}
+}
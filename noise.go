@@ -0,0 +1,206 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"math/rand/v2"
+)
+
+const permSize = 256
+
+// Noise is a 2D coherent noise function, it returns values in range [-1, 1].
+type Noise func(x, y float64) float64
+
+// NoiseConfig holds settings for [Fractal] noise, zero values are replaced with defaults.
+type NoiseConfig struct {
+	// Octaves is a number of summed noise layers, defaults to one.
+	Octaves int
+	// Frequency is a frequency of first octave, defaults to one.
+	Frequency float64
+	// Persistence is an amplitude multiplier for every next octave, defaults to 0.5.
+	Persistence float64
+	// Lacunarity is a frequency multiplier for every next octave, defaults to two.
+	Lacunarity float64
+}
+
+var gradients = [8][2]float64{
+	{1, 1}, {-1, 1}, {1, -1}, {-1, -1},
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+}
+
+const gradMask = len(gradients) - 1
+
+// NewPerlin creates Perlin (gradient) noise, permutation table is shuffled with given source.
+func NewPerlin(src rand.Source) Noise {
+	perm := newPermutation(src)
+
+	return func(x, y float64) float64 {
+		var (
+			x0, y0 = math.Floor(x), math.Floor(y)
+			fx, fy = x - x0, y - y0
+			ix, iy = int(x0) & (permSize - 1), int(y0) & (permSize - 1)
+			u, v   = fade(fx), fade(fy)
+		)
+
+		grad := func(dx, dy int) float64 {
+			g := gradients[perm[perm[ix+dx]+iy+dy]&gradMask]
+
+			return g[0]*(fx-float64(dx)) + g[1]*(fy-float64(dy))
+		}
+
+		return clampUnit(lerp(
+			lerp(grad(0, 0), grad(1, 0), u),
+			lerp(grad(0, 1), grad(1, 1), u),
+			v,
+		))
+	}
+}
+
+// NewSimplex creates simplex noise, permutation table is shuffled with given source.
+func NewSimplex(src rand.Source) Noise {
+	const (
+		scale   = 70.0
+		falloff = 0.5
+		sqrt3   = 1.7320508075688772
+		f2      = (sqrt3 - 1) / 2 // skews input space to simplex grid
+		g2      = (3 - sqrt3) / 6 // unskews it back
+		g3      = 2*g2 - 1        // offset of the last corner
+	)
+
+	perm := newPermutation(src)
+
+	return func(x, y float64) float64 {
+		var (
+			s      = (x + y) * f2
+			i, j   = math.Floor(x + s), math.Floor(y + s)
+			t      = (i + j) * g2
+			x0, y0 = x - (i - t), y - (j - t)
+			i1, j1 = 0, 1
+			ii, jj = int(i) & (permSize - 1), int(j) & (permSize - 1)
+			total  float64
+		)
+
+		if x0 > y0 {
+			i1, j1 = 1, 0
+		}
+
+		corner := func(dx, dy float64, ci, cj int) {
+			if k := falloff - dx*dx - dy*dy; k > 0 {
+				g := gradients[perm[ii+ci+perm[jj+cj]]&gradMask]
+				k *= k
+				total += k * k * (g[0]*dx + g[1]*dy)
+			}
+		}
+
+		corner(x0, y0, 0, 0)
+		corner(x0-float64(i1)+g2, y0-float64(j1)+g2, i1, j1)
+		corner(x0+g3, y0+g3, 1, 1)
+
+		return clampUnit(scale * total)
+	}
+}
+
+// NewValue creates value noise: random values in lattice points, smoothly interpolated, lattice is shuffled with
+// given source.
+func NewValue(src rand.Source) Noise {
+	const scale = 2.0 / (permSize - 1)
+
+	perm := newPermutation(src)
+
+	return func(x, y float64) float64 {
+		var (
+			x0, y0 = math.Floor(x), math.Floor(y)
+			ix, iy = int(x0) & (permSize - 1), int(y0) & (permSize - 1)
+			u, v   = fade(x - x0), fade(y - y0)
+		)
+
+		value := func(dx, dy int) float64 {
+			return float64(perm[perm[ix+dx]+iy+dy])*scale - 1
+		}
+
+		return lerp(
+			lerp(value(0, 0), value(1, 0), u),
+			lerp(value(0, 1), value(1, 1), u),
+			v,
+		)
+	}
+}
+
+// Fractal sums octaves of given noise (fractal Brownian motion), result is normalized to range [-1, 1].
+func Fractal(n Noise, cfg NoiseConfig) Noise {
+	const (
+		defaultOctaves     = 1
+		defaultFrequency   = 1.0
+		defaultPersistence = 0.5
+		defaultLacunarity  = 2.0
+	)
+
+	cfg.Octaves = orDefault(cfg.Octaves, defaultOctaves)
+	cfg.Frequency = orDefault(cfg.Frequency, defaultFrequency)
+	cfg.Persistence = orDefault(cfg.Persistence, defaultPersistence)
+	cfg.Lacunarity = orDefault(cfg.Lacunarity, defaultLacunarity)
+
+	return func(x, y float64) float64 {
+		var (
+			freq  = cfg.Frequency
+			amp   = one
+			total float64
+			norm  float64
+		)
+
+		for i := 0; i < cfg.Octaves; i++ {
+			total += n(x*freq, y*freq) * amp
+			norm += amp
+			freq *= cfg.Lacunarity
+			amp *= cfg.Persistence
+		}
+
+		return total / norm
+	}
+}
+
+// FillNoise fills map with values, made from noise sampled at cell centers.
+func (m *Map[T]) FillNoise(
+	n Noise,
+	value func(image.Point, float64) T,
+) {
+	m.cells.Iter(func(x, y int, _ T) (next bool) {
+		p := image.Pt(x, y)
+
+		m.Set(p, value(p, n(float64(x)+half, float64(y)+half)))
+
+		return true
+	})
+}
+
+// newPermutation returns shuffled permutation of [0, 256), repeated twice to avoid index wrapping.
+func newPermutation(src rand.Source) (perm []int) {
+	perm = make([]int, permSize+permSize)
+
+	for i := 0; i < permSize; i++ {
+		perm[i] = i
+	}
+
+	rand.New(src).Shuffle(permSize, func(i, j int) {
+		perm[i], perm[j] = perm[j], perm[i]
+	})
+
+	copy(perm[permSize:], perm[:permSize])
+
+	return perm
+}
+
+// fade is a quintic smoothstep 6t^5 - 15t^4 + 10t^3.
+func fade(t float64) float64 {
+	const a, b, c = 6, 15, 10
+
+	return t * t * t * (t*(t*a-b) + c)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(-one, math.Min(one, v))
+}
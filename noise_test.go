@@ -0,0 +1,144 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func newNoises(seed uint64) map[string]Noise {
+	return map[string]Noise{
+		"perlin":  NewPerlin(rand.NewPCG(seed, seed+1)),
+		"simplex": NewSimplex(rand.NewPCG(seed, seed+1)),
+		"value":   NewValue(rand.NewPCG(seed, seed+1)),
+	}
+}
+
+func TestNoiseGolden(t *testing.T) {
+	t.Parallel()
+
+	const eps = 1e-9
+
+	var (
+		noises = newNoises(1)
+		points = [][2]float64{{0.5, 0.5}, {1.25, 3.75}, {-7.3, 12.9}, {100.1, -42.6}}
+		golden = map[string][]float64{
+			"perlin":      {0.25, 0.007284164429, 0.206937912000, -0.112618844160},
+			"simplex":     {0.307156513627, -0.203945223798, -0.658213884619, 0.628709466826},
+			"value":       {0.309803921569, -0.271745988434, -0.633183459213, -0.562644173001},
+			"fbm perlin":  {0.099030960074, 0.206387240161, 0.335379025300, 0.126000240747},
+			"fbm simplex": {0.388544164974, 0.592510682352, -0.595339921704, -0.413284626859},
+			"fbm value":   {0.009653160752, 0.076627063708, 0.086599890760, -0.347823632277},
+		}
+	)
+
+	for name, n := range newNoises(1) {
+		noises["fbm "+name] = Fractal(n, NoiseConfig{Octaves: 4, Frequency: 0.05})
+	}
+
+	for name, want := range golden {
+		for i, p := range points {
+			if got := noises[name](p[0], p[1]); math.Abs(got-want[i]) > eps {
+				t.Fatalf("%s %v: got %.12f want %.12f", name, p, got, want[i])
+			}
+		}
+	}
+}
+
+func TestNoiseRange(t *testing.T) {
+	t.Parallel()
+
+	const (
+		samples = 20000
+		step    = 0.01
+		maxStep = 0.15
+	)
+
+	rng := rand.New(rand.NewPCG(9, 9))
+
+	for name, n := range newNoises(7) {
+		var (
+			other = newNoises(8)[name]
+			diff  bool
+		)
+
+		for i := 0; i < samples; i++ {
+			x, y := rng.Float64()*600-300, rng.Float64()*600-300
+			v := n(x, y)
+
+			if v < -1 || v > 1 {
+				t.Fatalf("%s (%f, %f): %f out of range", name, x, y, v)
+			}
+
+			// continuity
+			if d := math.Abs(n(x+step, y+step) - v); d > maxStep {
+				t.Fatalf("%s (%f, %f): step %f", name, x, y, d)
+			}
+
+			diff = diff || other(x, y) != v
+		}
+
+		if !diff {
+			t.Fatalf("%s: seed ignored", name)
+		}
+	}
+}
+
+func TestNoiseFractal(t *testing.T) {
+	t.Parallel()
+
+	var (
+		n = NewPerlin(rand.NewPCG(1, 1))
+		f = Fractal(n, NoiseConfig{})
+		g = Fractal(n, NoiseConfig{Octaves: 2, Persistence: 1, Lacunarity: 3})
+	)
+
+	for _, p := range [][2]float64{{0.3, 0.7}, {-5.5, 2.1}, {40.2, 40.9}} {
+		if f(p[0], p[1]) != n(p[0], p[1]) {
+			t.Fatal("single octave differs")
+		}
+
+		if want := (n(p[0], p[1]) + n(p[0]*3, p[1]*3)) / 2; math.Abs(g(p[0], p[1])-want) > 1e-12 {
+			t.Fatal("octaves sum")
+		}
+	}
+}
+
+func TestMapFillNoise(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 12, 6
+
+	var (
+		m      = New[byte](image.Rect(0, 0, W, H))
+		noise  = Fractal(NewSimplex(rand.NewPCG(42, 42)), NoiseConfig{Octaves: 3, Frequency: 0.15})
+		golden = []string{
+			"~~~~~~~.~~~.",
+			".....~~..~~.",
+			"^.........~~",
+			"^.^..~..^...",
+			"^^..~~~^^.^.",
+			"^^.....^^...",
+		}
+	)
+
+	m.FillNoise(noise, func(_ image.Point, v float64) byte {
+		switch {
+		case v < -0.2:
+			return '~'
+		case v < 0.3:
+			return '.'
+		}
+
+		return '^'
+	})
+
+	for y, row := range golden {
+		for x, c := range []byte(row) {
+			if got := m.MustGet(image.Pt(x, y)); got != c {
+				t.Fatalf("(%d, %d): got %q want %q", x, y, got, c)
+			}
+		}
+	}
+}
@@ -20,7 +20,7 @@ func (p *path) Last() (v image.Point) {
 func (p *path) Points() (rv []image.Point) {
 	rv = make([]image.Point, p.length)
 
-	for i := p.length - 1; i > 0; i-- {
+	for i := p.length - 1; i >= 0; i-- {
 		rv[i], p = p.Point, p.Parent
 	}
 
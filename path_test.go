@@ -29,3 +29,21 @@ func TestPath(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestPathPoints(t *testing.T) {
+	t.Parallel()
+
+	const N = 5
+
+	var p *path
+
+	for i := 0; i < N; i++ {
+		p = p.Fork(image.Pt(i, N-i), float64(i))
+	}
+
+	for i, pt := range p.Points() {
+		if pt != image.Pt(i, N-i) {
+			t.Fatalf("point %d: %v", i, pt)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package grid
+
+import (
+	"image"
+	"slices"
+
+	"github.com/s0rg/set"
+)
+
+// PathOptions holds path-finding parameters, its address is a part of [PathCache] key.
+type PathOptions[T any] struct {
+	Dist Distance
+	Cost Cost[T]
+	Dirs []image.Point
+}
+
+type pathKey[T any] struct {
+	Opts     *PathOptions[T]
+	Src, Dst image.Point
+}
+
+// PathCache caches results of [Map.Path], cached paths are dropped as soon as
+// any of their cells is changed via [Map.Set] (or whole map via [Map.Fill]).
+type PathCache[T any] struct {
+	m     *Map[T]
+	paths map[pathKey[T]][]image.Point
+	cells map[image.Point]set.Unordered[pathKey[T]]
+}
+
+// NewPathCache creates empty [PathCache] bound to given map.
+func NewPathCache[T any](m *Map[T]) (rv *PathCache[T]) {
+	rv = &PathCache[T]{
+		m:     m,
+		paths: make(map[pathKey[T]][]image.Point),
+		cells: make(map[image.Point]set.Unordered[pathKey[T]]),
+	}
+
+	m.watch(rv)
+
+	return rv
+}
+
+// Path returns cached path for given points and options, performing path finding on cache miss.
+// Options are matched by address, not by value, so same [PathOptions] must be reused for every call:
+// options, created per call, never hit the cache, and their paths are kept until any of their cells is changed
+// (or [PathCache.Reset] is called).
+func (pc *PathCache[T]) Path(
+	src, dst image.Point,
+	opts *PathOptions[T],
+) (rv []image.Point, ok bool) {
+	key := pathKey[T]{Src: src, Dst: dst, Opts: opts}
+
+	if rv, ok = pc.paths[key]; ok {
+		return slices.Clone(rv), true
+	}
+
+	if rv, ok = pc.m.Path(src, dst, opts.Dirs, opts.Dist, opts.Cost); !ok {
+		return nil, false
+	}
+
+	pc.paths[key] = rv
+
+	for _, p := range rv {
+		keys, found := pc.cells[p]
+		if !found {
+			keys = make(set.Unordered[pathKey[T]])
+			pc.cells[p] = keys
+		}
+
+		keys.Add(key)
+	}
+
+	return slices.Clone(rv), true
+}
+
+// Invalidate drops all cached paths, that traverse given point.
+// Use it if cell was modified in-place, not via [Map.Set].
+func (pc *PathCache[T]) Invalidate(p image.Point) {
+	keys, ok := pc.cells[p]
+	if !ok {
+		return
+	}
+
+	for key := range keys {
+		for _, c := range pc.paths[key] {
+			if c.Eq(p) {
+				continue
+			}
+
+			if other, found := pc.cells[c]; found {
+				other.Del(key)
+
+				if other.Len() == 0 {
+					delete(pc.cells, c)
+				}
+			}
+		}
+
+		delete(pc.paths, key)
+	}
+
+	delete(pc.cells, p)
+}
+
+// Len returns number of cached paths.
+func (pc *PathCache[T]) Len() int {
+	return len(pc.paths)
+}
+
+// Reset drops all cached paths.
+func (pc *PathCache[T]) Reset() {
+	clear(pc.paths)
+	clear(pc.cells)
+}
+
+// Close detaches cache from its map, cache must not be used after this call.
+func (pc *PathCache[T]) Close() {
+	pc.m.unwatch(pc)
+	pc.Reset()
+}
+
+func (pc *PathCache[T]) changed(p image.Point) {
+	pc.Invalidate(p)
+}
+
+func (pc *PathCache[T]) reset() {
+	pc.Reset()
+}
@@ -0,0 +1,146 @@
+package grid
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPathCache(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 5, 5
+
+	var (
+		src  = image.Pt(0, 0)
+		dst  = image.Pt(4, 0)
+		opts = &PathOptions[bool]{
+			Dirs: Points(DirectionsCardinal...),
+			Dist: DistanceManhattan,
+			Cost: func(_ image.Point, d float64, wall bool) (cost float64, walkable bool) {
+				return d, !wall
+			},
+		}
+	)
+
+	m := New[bool](image.Rect(0, 0, W, H))
+	c := NewPathCache(m)
+
+	p1, ok := c.Path(src, dst, opts)
+	if !ok || len(p1) != W {
+		t.Fatal("step 1")
+	}
+
+	if c.Len() != 1 {
+		t.Fatal("step 1 - len")
+	}
+
+	p2, ok := c.Path(src, dst, opts)
+	if !ok || len(p2) != len(p1) {
+		t.Fatal("step 2")
+	}
+
+	// cell not on path
+	m.Set(image.Pt(2, 4), true)
+
+	if c.Len() != 1 {
+		t.Fatal("step 3")
+	}
+
+	// cell on path
+	m.Set(image.Pt(2, 0), true)
+
+	if c.Len() != 0 {
+		t.Fatal("step 4")
+	}
+
+	p3, ok := c.Path(src, dst, opts)
+	if !ok || len(p3) != W+2 {
+		t.Fatal("step 5")
+	}
+
+	if !m.ValidatePath(p3, opts.Dist, opts.Cost) {
+		t.Fatal("step 5 - validate")
+	}
+
+	if _, ok = c.Path(src, image.Pt(2, 0), opts); ok {
+		t.Fatal("step 6")
+	}
+
+	c.Invalidate(image.Pt(4, 4))
+
+	if c.Len() != 1 {
+		t.Fatal("step 7")
+	}
+
+	c.Close()
+
+	if c.Len() != 0 {
+		t.Fatal("step 8")
+	}
+
+	m.Set(p3[1], true)
+}
+
+func TestPathCacheOptions(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 5, 5
+
+	var (
+		src    = image.Pt(0, 0)
+		dst    = image.Pt(3, 3)
+		coster = func(_ image.Point, d float64, _ bool) (cost float64, walkable bool) {
+			return d, true
+		}
+		cross = &PathOptions[bool]{Dirs: Points(DirectionsCardinal...), Dist: DistanceManhattan, Cost: coster}
+		all   = &PathOptions[bool]{Dirs: Points(DirectionsALL...), Dist: DistanceChebyshev, Cost: coster}
+	)
+
+	m := New[bool](image.Rect(0, 0, W, H))
+	c := NewPathCache(m)
+
+	c.Path(src, dst, cross)
+	c.Path(src, dst, all)
+
+	if c.Len() != 2 {
+		t.Fatal("len")
+	}
+
+	m.Set(src, false)
+
+	if c.Len() != 0 {
+		t.Fatal("invalidate")
+	}
+}
+
+func TestPathCacheFill(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 5, 5
+
+	var (
+		m    = New[bool](image.Rect(0, 0, W, H))
+		c    = NewPathCache(m)
+		opts = &PathOptions[bool]{
+			Dirs: Points(DirectionsCardinal...),
+			Dist: DistanceManhattan,
+			Cost: func(_ image.Point, d float64, wall bool) (cost float64, walkable bool) {
+				return d, !wall
+			},
+		}
+	)
+
+	if _, ok := c.Path(image.Pt(0, 0), image.Pt(4, 4), opts); !ok || c.Len() != 1 {
+		t.Fatal("step 1")
+	}
+
+	m.Fill(func() bool { return true })
+
+	if c.Len() != 0 {
+		t.Fatal("step 2 - not reset")
+	}
+
+	if _, ok := c.Path(image.Pt(0, 0), image.Pt(4, 4), opts); ok {
+		t.Fatal("step 3 - stale path")
+	}
+}
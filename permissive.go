@@ -0,0 +1,195 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"slices"
+)
+
+// permLine is a line between two grid corners, used by permissive fov.
+type permLine struct {
+	xi, yi, xf, yf int
+}
+
+func (l *permLine) relativeSlope(x, y int) int {
+	return (l.yf-l.yi)*(l.xf-x) - (l.xf-l.xi)*(l.yf-y)
+}
+
+func (l *permLine) isBelow(x, y int) bool           { return l.relativeSlope(x, y) > 0 }
+func (l *permLine) isBelowOrContains(x, y int) bool { return l.relativeSlope(x, y) >= 0 }
+func (l *permLine) isAbove(x, y int) bool           { return l.relativeSlope(x, y) < 0 }
+func (l *permLine) isAboveOrContains(x, y int) bool { return l.relativeSlope(x, y) <= 0 }
+func (l *permLine) contains(x, y int) bool          { return l.relativeSlope(x, y) == 0 }
+
+func (l *permLine) isCollinear(o *permLine) bool {
+	return l.contains(o.xi, o.yi) && l.contains(o.xf, o.yf)
+}
+
+type permBump struct {
+	parent *permBump
+	x, y   int
+}
+
+type permView struct {
+	shallowBump, steepBump *permBump
+	shallow, steep         permLine
+}
+
+type permissive[T any] struct {
+	m       *Map[T]
+	cast    Cast[T]
+	seen    map[image.Point]bool
+	views   []*permView
+	src     image.Point
+	distMax float64
+	index   int
+}
+
+// CastPermissive performs precise permissive field of view (by Jonathon Duerig), cell is visible if any
+// line between source cell and target cell is not blocked. Cast is called exactly once for every visible cell,
+// it must return false for cells, that blocks the light.
+func (m *Map[T]) CastPermissive(
+	src image.Point,
+	distMax float64,
+	cast Cast[T],
+) {
+	val, ok := m.cells.Get(src.X, src.Y)
+	if !ok {
+		return
+	}
+
+	var (
+		w, h   = m.cells.Bounds()
+		radius = int(math.Min(distMax, float64(max(w, h))))
+		minX   = min(src.X, radius)
+		maxX   = min(w-src.X-1, radius)
+		minY   = min(src.Y, radius)
+		maxY   = min(h-src.Y-1, radius)
+	)
+
+	p := permissive[T]{
+		m:       m,
+		cast:    cast,
+		src:     src,
+		distMax: distMax,
+		seen:    make(map[image.Point]bool),
+	}
+
+	p.seen[src] = !cast(src, 0, val)
+
+	p.quadrant(1, 1, maxX, maxY, radius+1)
+	p.quadrant(1, -1, maxX, minY, radius+1)
+	p.quadrant(-1, -1, minX, minY, radius+1)
+	p.quadrant(-1, 1, minX, maxY, radius+1)
+}
+
+func (p *permissive[T]) quadrant(dx, dy, extX, extY, lim int) {
+	p.views = append(p.views[:0], &permView{
+		shallow: permLine{xi: 0, yi: 1, xf: lim, yf: 0},
+		steep:   permLine{xi: 1, yi: 0, xf: 0, yf: lim},
+	})
+
+	for i := 1; i <= extX+extY && len(p.views) > 0; i++ {
+		p.index = 0
+
+		for j := max(0, i-extX); j <= min(i, extY) && p.index < len(p.views); j++ {
+			p.visit(i-j, j, dx, dy)
+		}
+	}
+}
+
+func (p *permissive[T]) visit(x, y, dx, dy int) {
+	// top-left and bottom-right corners of current cell
+	tlX, tlY, brX, brY := x, y+1, x+1, y
+
+	for p.index < len(p.views) && p.views[p.index].steep.isBelowOrContains(brX, brY) {
+		p.index++
+	}
+
+	if p.index == len(p.views) || p.views[p.index].shallow.isAboveOrContains(tlX, tlY) {
+		return
+	}
+
+	if !p.blocks(p.src.Add(image.Pt(x*dx, y*dy))) {
+		return
+	}
+
+	view := p.views[p.index]
+
+	switch shallow, steep := view.shallow.isAbove(brX, brY), view.steep.isBelow(tlX, tlY); {
+	case shallow && steep:
+		p.views = slices.Delete(p.views, p.index, p.index+1)
+	case shallow:
+		addShallowBump(view, tlX, tlY)
+		p.check(p.index)
+	case steep:
+		addSteepBump(view, brX, brY)
+		p.check(p.index)
+	default:
+		clone := *view
+		p.views = slices.Insert(p.views, p.index, &clone)
+
+		addSteepBump(&clone, brX, brY)
+
+		if p.check(p.index) {
+			p.index++
+		}
+
+		addShallowBump(view, tlX, tlY)
+		p.check(p.index)
+	}
+}
+
+// blocks reports cell to caller (if not reported yet) and returns true if it blocks the light.
+func (p *permissive[T]) blocks(pt image.Point) bool {
+	dist := DistanceEuclidean(p.src, pt)
+	if dist > p.distMax {
+		return true
+	}
+
+	wall, seen := p.seen[pt]
+	if !seen {
+		val, _ := p.m.cells.Get(pt.X, pt.Y) // quadrant extents are clipped by map bounds
+
+		wall = !p.cast(pt, dist, val)
+		p.seen[pt] = wall
+	}
+
+	return wall
+}
+
+// check removes view at given index, if it has collapsed to the line, that passes trough source cell corner.
+func (p *permissive[T]) check(index int) (ok bool) {
+	view := p.views[index]
+
+	if view.shallow.isCollinear(&view.steep) &&
+		(view.shallow.contains(0, 1) || view.shallow.contains(1, 0)) {
+		p.views = slices.Delete(p.views, index, index+1)
+
+		return false
+	}
+
+	return true
+}
+
+func addShallowBump(v *permView, x, y int) {
+	v.shallow.xf, v.shallow.yf = x, y
+	v.shallowBump = &permBump{x: x, y: y, parent: v.shallowBump}
+
+	for b := v.steepBump; b != nil; b = b.parent {
+		if v.shallow.isAbove(b.x, b.y) {
+			v.shallow.xi, v.shallow.yi = b.x, b.y
+		}
+	}
+}
+
+func addSteepBump(v *permView, x, y int) {
+	v.steep.xf, v.steep.yf = x, y
+	v.steepBump = &permBump{x: x, y: y, parent: v.steepBump}
+
+	for b := v.shallowBump; b != nil; b = b.parent {
+		if v.steep.isBelow(b.x, b.y) {
+			v.steep.xi, v.steep.yi = b.x, b.y
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"math/rand/v2"
+)
+
+// Spacing is a per-cell distance callback.
+type Spacing[T any] func(image.Point, T) float64
+
+type poissonGen[T any] struct {
+	m       *Map[T]
+	rng     *rand.Rand
+	pass    Iter[T]
+	spacing Spacing[T]
+	buckets map[image.Point][]int
+	points  []image.Point
+	radii   []float64
+	minDist float64
+	maxDist float64
+	size    float64
+}
+
+// PoissonDisc scatters cells, that match given predicate, by Bridson's algorithm, so that distance between any two
+// of them is at least minimal distance (values below one are treated as one). Spacing callback (if not nil)
+// overrides distance around cell, pair of cells keeps the larger distance of two, values below minimal distance
+// are ignored. Cells are returned in order of placement, every matching cell region is filled, even if disconnected.
+func (m *Map[T]) PoissonDisc(
+	src rand.Source,
+	minDist float64,
+	pass Iter[T],
+	spacing Spacing[T],
+) (rv []image.Point) {
+	const sqrt2 = math.Sqrt2
+
+	minDist = math.Max(minDist, one)
+
+	g := &poissonGen[T]{
+		m:       m,
+		rng:     rand.New(src),
+		pass:    pass,
+		spacing: spacing,
+		buckets: make(map[image.Point][]int),
+		minDist: minDist,
+		maxDist: minDist,
+		size:    minDist / sqrt2,
+	}
+
+	var cells []image.Point
+
+	m.cells.Iter(func(x, y int, v T) (next bool) {
+		if p := image.Pt(x, y); pass(p, v) {
+			cells = append(cells, p)
+		}
+
+		return true
+	})
+
+	g.rng.Shuffle(len(cells), func(i, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+
+	for _, p := range cells {
+		if r, ok := g.check(p); ok {
+			g.grow(g.add(p, r))
+		}
+	}
+
+	return g.points
+}
+
+// grow runs Bridson's algorithm from given sample.
+func (g *poissonGen[T]) grow(start int) {
+	const (
+		attempts = 30
+		fullTurn = 2 * math.Pi
+	)
+
+	active := []int{start}
+
+	for len(active) > 0 {
+		var (
+			k      = g.rng.IntN(len(active))
+			cur    = active[k]
+			origin = g.points[cur]
+			r      = g.radii[cur]
+			placed bool
+		)
+
+		for i := 0; i < attempts; i++ {
+			var (
+				angle = g.rng.Float64() * fullTurn
+				dist  = r * (1 + g.rng.Float64())
+				s, c  = math.Sincos(angle)
+				p     = image.Pt(
+					int(math.Floor(float64(origin.X)+half+c*dist)),
+					int(math.Floor(float64(origin.Y)+half+s*dist)),
+				)
+			)
+
+			if pr, ok := g.check(p); ok {
+				active = append(active, g.add(p, pr))
+				placed = true
+
+				break
+			}
+		}
+
+		if !placed {
+			active[k] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+}
+
+// check reports if sample may be placed at given cell, and returns its distance.
+func (g *poissonGen[T]) check(p image.Point) (r float64, ok bool) {
+	var v T
+
+	if v, ok = g.m.cells.Get(p.X, p.Y); !ok || !g.pass(p, v) {
+		return 0, false
+	}
+
+	r = g.minDist
+
+	if g.spacing != nil {
+		r = math.Max(r, g.spacing(p, v))
+	}
+
+	var (
+		b     = g.bucket(p)
+		reach = int(math.Ceil(math.Max(r, g.maxDist) / g.size))
+	)
+
+	for y := b.Y - reach; y <= b.Y+reach; y++ {
+		for x := b.X - reach; x <= b.X+reach; x++ {
+			for _, i := range g.buckets[image.Pt(x, y)] {
+				if DistanceEuclidean(p, g.points[i]) < math.Max(r, g.radii[i]) {
+					return 0, false
+				}
+			}
+		}
+	}
+
+	return r, true
+}
+
+func (g *poissonGen[T]) add(p image.Point, r float64) (idx int) {
+	idx = len(g.points)
+	b := g.bucket(p)
+
+	g.points = append(g.points, p)
+	g.radii = append(g.radii, r)
+	g.buckets[b] = append(g.buckets[b], idx)
+	g.maxDist = math.Max(g.maxDist, r)
+
+	return idx
+}
+
+func (g *poissonGen[T]) bucket(p image.Point) image.Point {
+	return image.Pt(
+		int(math.Floor(float64(p.X)/g.size)),
+		int(math.Floor(float64(p.Y)/g.size)),
+	)
+}
@@ -0,0 +1,132 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func checkPoisson(t *testing.T, m *Map[bool], points []image.Point, radius func(image.Point) float64) {
+	t.Helper()
+
+	for i, a := range points {
+		if m.MustGet(a) {
+			t.Fatalf("%v: is a wall", a)
+		}
+
+		for _, b := range points[i+1:] {
+			if d := DistanceEuclidean(a, b); d < math.Max(radius(a), radius(b)) {
+				t.Fatalf("%v - %v: distance %f", a, b, d)
+			}
+		}
+	}
+
+	// every free cell is covered
+	m.Iter(func(p image.Point, wall bool) bool {
+		if wall {
+			return true
+		}
+
+		if !slices.ContainsFunc(points, func(q image.Point) bool {
+			return DistanceEuclidean(p, q) < math.Max(radius(p), radius(q))
+		}) {
+			t.Fatalf("%v: not covered", p)
+		}
+
+		return true
+	})
+}
+
+func TestMapPoissonDisc(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H   = 50, 40
+		radius = 4.5
+	)
+
+	for seed := uint64(1); seed <= 4; seed++ {
+		var (
+			m      = randomWalls(seed, W, H, 0.25)
+			points = m.PoissonDisc(rand.NewPCG(seed, seed), radius, isFloor, nil)
+			same   = m.PoissonDisc(rand.NewPCG(seed, seed), radius, isFloor, nil)
+			other  = m.PoissonDisc(rand.NewPCG(seed+1, seed), radius, isFloor, nil)
+		)
+
+		if len(points) < 50 {
+			t.Fatalf("seed %d: %d points", seed, len(points))
+		}
+
+		checkPoisson(t, m, points, func(image.Point) float64 { return radius })
+
+		if !slices.Equal(points, same) || slices.Equal(points, other) {
+			t.Fatalf("seed %d: not reproducible", seed)
+		}
+	}
+}
+
+func TestMapPoissonDiscIslands(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 30, 10
+
+	m := New[bool](image.Rect(0, 0, W, H))
+
+	// three islands in the sea
+	m.Iter(func(p image.Point, _ bool) bool {
+		m.Set(p, p.X%10 > 3)
+
+		return true
+	})
+
+	points := m.PoissonDisc(rand.NewPCG(1, 1), 0, isFloor, nil)
+
+	if len(points) != 3*4*H {
+		t.Fatal("points:", len(points))
+	}
+
+	points = m.PoissonDisc(rand.NewPCG(1, 1), 20, isFloor, nil)
+
+	checkPoisson(t, m, points, func(image.Point) float64 { return 20 })
+
+	if len(points) < 2 {
+		t.Fatal("islands skipped:", points)
+	}
+}
+
+func TestMapPoissonDiscSpacing(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 60, 30
+
+	var (
+		m      = New[bool](image.Rect(0, 0, W, H))
+		radius = func(p image.Point) float64 {
+			if p.X < W/2 {
+				return 6
+			}
+
+			return 1 // below minimal
+		}
+		points = m.PoissonDisc(rand.NewPCG(3, 3), 2, isFloor, func(p image.Point, _ bool) float64 {
+			return radius(p)
+		})
+		left, right int
+	)
+
+	checkPoisson(t, m, points, func(p image.Point) float64 { return math.Max(radius(p), 2) })
+
+	for _, p := range points {
+		if p.X < W/2 {
+			left++
+		} else {
+			right++
+		}
+	}
+
+	if right < 4*left {
+		t.Fatalf("density: left %d right %d", left, right)
+	}
+}
@@ -0,0 +1,105 @@
+package grid
+
+import (
+	"image"
+	"math"
+
+	"github.com/s0rg/vec2d"
+)
+
+// RaySide is a kind of cell border, crossed by the ray.
+type RaySide uint8
+
+const (
+	// SideVertical is a vertical border (ray moved along X axis).
+	SideVertical RaySide = iota
+	// SideHorizontal is a horizontal border (ray moved along Y axis).
+	SideHorizontal
+)
+
+// RayHit describes ray entering the cell.
+type RayHit struct {
+	Point  vec2d.V[float64] // exact point, where ray enters the cell
+	Cell   image.Point      // cell coordinates
+	Normal image.Point      // normal of crossed cell face, points back to the ray origin
+	Dist   float64          // distance from ray origin to Point
+	Side   RaySide          // kind of crossed border
+}
+
+// RayCast is a precise ray-casting callback.
+type RayCast[T any] func(RayHit, T) bool
+
+// CastRayFrom performs DDA ray cast from given (sub-cell) origin with given angle (in degrees), limited by given
+// max distance. Cast is called for every traversed cell, except the origin one.
+func (m *Map[T]) CastRayFrom(
+	origin vec2d.V[float64],
+	angle, distMax float64,
+	cast RayCast[T],
+) {
+	if _, ok := m.cells.Get(int(math.Floor(origin.X)), int(math.Floor(origin.Y))); !ok {
+		return
+	}
+
+	s, c := math.Sincos(radians(angle))
+
+	walkRay(origin, vec2d.New(c, s), distMax, func(h *RayHit) (ok bool) {
+		var val T
+
+		if val, ok = m.cells.Get(h.Cell.X, h.Cell.Y); !ok {
+			return false
+		}
+
+		return cast(*h, val)
+	})
+}
+
+// walkRay performs DDA traversal from origin (in cell units) in given direction, it calls fn for every
+// traversed cell (origin cell excluded), hit distance is measured from origin to the point, where ray
+// enters the cell.
+func walkRay(
+	origin, dir vec2d.V[float64],
+	distMax float64,
+	fn func(*RayHit) bool,
+) {
+	var (
+		hit          = RayHit{Cell: image.Pt(int(math.Floor(origin.X)), int(math.Floor(origin.Y)))}
+		stepX, sideX = rayAxis(origin.X, dir.X, hit.Cell.X)
+		stepY, sideY = rayAxis(origin.Y, dir.Y, hit.Cell.Y)
+		unitX        = math.Abs(one / dir.X)
+		unitY        = math.Abs(one / dir.Y)
+	)
+
+	for {
+		if sideX < sideY {
+			hit.Dist, hit.Side, hit.Normal = sideX, SideVertical, image.Pt(-stepX, 0)
+			sideX += unitX
+			hit.Cell.X += stepX
+		} else {
+			hit.Dist, hit.Side, hit.Normal = sideY, SideHorizontal, image.Pt(0, -stepY)
+			sideY += unitY
+			hit.Cell.Y += stepY
+		}
+
+		if hit.Dist > distMax {
+			break
+		}
+
+		hit.Point = origin.Add(dir.MulScalar(hit.Dist))
+
+		if !fn(&hit) {
+			break
+		}
+	}
+}
+
+// rayAxis returns step direction and distance (along the ray) to the first cell border for single axis.
+func rayAxis(pos, dir float64, cell int) (step int, side float64) {
+	switch {
+	case dir < 0:
+		return -1, (pos - float64(cell)) / -dir
+	case dir > 0:
+		return 1, (float64(cell) + one - pos) / dir
+	}
+
+	return 0, math.Inf(1)
+}
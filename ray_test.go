@@ -0,0 +1,217 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/s0rg/vec2d"
+)
+
+func TestMapCastRayFrom(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 10, 10
+		eps  = 1e-9
+	)
+
+	var (
+		m      = New[bool](image.Rect(0, 0, W, H))
+		origin = vec2d.New(3.4, 7.8)
+		cases  = []struct {
+			Hit   RayHit
+			Angle float64
+		}{
+			{
+				Angle: 0,
+				Hit: RayHit{
+					Point:  vec2d.New(4.0, 7.8),
+					Cell:   image.Pt(4, 7),
+					Normal: image.Pt(-1, 0),
+					Dist:   0.6,
+					Side:   SideVertical,
+				},
+			},
+			{
+				Angle: 180,
+				Hit: RayHit{
+					Point:  vec2d.New(3.0, 7.8),
+					Cell:   image.Pt(2, 7),
+					Normal: image.Pt(1, 0),
+					Dist:   0.4,
+					Side:   SideVertical,
+				},
+			},
+			{
+				Angle: 270,
+				Hit: RayHit{
+					Point:  vec2d.New(3.4, 7.0),
+					Cell:   image.Pt(3, 6),
+					Normal: image.Pt(0, 1),
+					Dist:   0.8,
+					Side:   SideHorizontal,
+				},
+			},
+			{
+				Angle: 90,
+				Hit: RayHit{
+					Point:  vec2d.New(3.4, 8.0),
+					Cell:   image.Pt(3, 8),
+					Normal: image.Pt(0, -1),
+					Dist:   0.2,
+					Side:   SideHorizontal,
+				},
+			},
+		}
+	)
+
+	for i, tc := range cases {
+		var first *RayHit
+
+		m.CastRayFrom(origin, tc.Angle, 10.0, func(h RayHit, _ bool) bool {
+			first = &h
+
+			return false
+		})
+
+		switch {
+		case first == nil:
+			t.Fatalf("case[%d] no hits", i)
+		case !first.Cell.Eq(tc.Hit.Cell), !first.Normal.Eq(tc.Hit.Normal), first.Side != tc.Hit.Side:
+			t.Fatalf("case[%d] want: %+v got: %+v", i, tc.Hit, *first)
+		case math.Abs(first.Dist-tc.Hit.Dist) > eps, first.Point.Sub(tc.Hit.Point).Len() > eps:
+			t.Fatalf("case[%d] want: %+v got: %+v", i, tc.Hit, *first)
+		}
+	}
+}
+
+func TestMapCastRayFromWall(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 10, 10
+
+	var (
+		m     = New[bool](image.Rect(0, 0, W, H))
+		wall  = image.Pt(6, 3)
+		cells []image.Point
+		last  RayHit
+	)
+
+	m.Set(wall, true)
+
+	m.CastRayFrom(vec2d.New(1.5, 3.5), 0, 20.0, func(h RayHit, w bool) bool {
+		cells, last = append(cells, h.Cell), h
+
+		return !w
+	})
+
+	if len(cells) != 5 || !last.Cell.Eq(wall) || last.Point.X != 6.0 {
+		t.Fatal(cells, last)
+	}
+
+	cells = cells[:0]
+
+	m.CastRayFrom(vec2d.New(1.5, 1.5), 45, 100.0, func(h RayHit, _ bool) bool {
+		cells = append(cells, h.Cell)
+
+		return true
+	})
+
+	if l := cells[len(cells)-1]; l.X != W-1 && l.Y != H-1 {
+		t.Fatal("last", l)
+	}
+
+	m.CastRayFrom(vec2d.New(-0.5, 1.5), 0, 10.0, func(_ RayHit, _ bool) bool {
+		t.Fatal("out-of-bounds origin")
+
+		return true
+	})
+}
+
+// segmentOverlap returns length of the part of segment (from origin, in direction dir, with given length),
+// that lies within the cell square, it returns negative value, if segment misses the cell.
+func segmentOverlap(origin, dir vec2d.V[float64], length float64, cell image.Point) float64 {
+	var (
+		t0, t1 = 0.0, length
+		pos    = [2]float64{origin.X, origin.Y}
+		del    = [2]float64{dir.X, dir.Y}
+		low    = [2]float64{float64(cell.X), float64(cell.Y)}
+	)
+
+	for i := 0; i < 2; i++ {
+		if del[i] == 0 {
+			if pos[i] < low[i] || pos[i] > low[i]+1 {
+				return -1
+			}
+
+			continue
+		}
+
+		a, b := (low[i]-pos[i])/del[i], (low[i]+1-pos[i])/del[i]
+		if a > b {
+			a, b = b, a
+		}
+
+		t0, t1 = math.Max(t0, a), math.Min(t1, b)
+	}
+
+	return t1 - t0
+}
+
+func TestMapCastRaySupercover(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 41, 41
+		dist = 17.3
+		step = 0.25
+		eps  = 1e-9
+	)
+
+	var (
+		m      = New[struct{}](image.Rect(0, 0, W, H))
+		src    = image.Pt(20, 20)
+		origin = vec2d.New(20.5, 20.5)
+	)
+
+	for angle := 0.0; angle < maxDegrees; angle += step {
+		var (
+			s, c  = math.Sincos(radians(angle))
+			dir   = vec2d.New(c, s)
+			seen  = make(map[image.Point]float64)
+			prev  = src
+			pdist float64
+		)
+
+		m.CastRay(src, angle, dist, func(p image.Point, d float64, _ struct{}) bool {
+			if dx, dy := abs(p.X-prev.X), abs(p.Y-prev.Y); dx+dy != 1 {
+				t.Fatalf("angle %f: %v is not adjacent to %v", angle, p, prev)
+			}
+
+			if d < pdist {
+				t.Fatalf("angle %f: %v distance decreased", angle, p)
+			}
+
+			if segmentOverlap(origin, dir, dist, p) < -eps {
+				t.Fatalf("angle %f: %v is not on the line", angle, p)
+			}
+
+			seen[p], prev, pdist = d, p, d
+
+			return true
+		})
+
+		m.Iter(func(p image.Point, _ struct{}) bool {
+			if p.Eq(src) {
+				return true
+			}
+
+			if _, ok := seen[p]; !ok && segmentOverlap(origin, dir, dist, p) > eps {
+				t.Fatalf("angle %f: %v is not traversed", angle, p)
+			}
+
+			return true
+		})
+	}
+}
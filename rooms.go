@@ -0,0 +1,209 @@
+package grid
+
+import (
+	"image"
+	"slices"
+)
+
+// CellKind is a cell class, found by [Map.Layout].
+type CellKind uint8
+
+const (
+	// CellBlocked marks impassable cells.
+	CellBlocked CellKind = iota
+	// CellRoom marks cells of open areas.
+	CellRoom
+	// CellCorridor marks narrow passages.
+	CellCorridor
+	// CellDoor marks corridor cells, that lead into rooms.
+	CellDoor
+	// CellDeadEnd marks corridor cells with single way out.
+	CellDeadEnd
+)
+
+// Layout is a map split into rooms and corridors.
+type Layout struct {
+	// Kinds holds class of every cell.
+	Kinds *Map[CellKind]
+	// Rooms holds connected areas of room cells.
+	Rooms *Components
+	// Links holds indices of rooms (in ascending order), that every room is connected to by corridors.
+	Links [][]int
+}
+
+// Layout classifies passable cells into rooms, corridors, doors and dead ends, and builds rooms adjacency graph.
+// Room cells are those covered by square of passable cells with given side, other passable cells are corridors,
+// connectivity is cardinal. Doors are corridor cells next to rooms, dead ends are corridor cells with (at most)
+// single passable neighbour, rooms are linked if they share a door or are joined by corridors.
+func (m *Map[T]) Layout(
+	side int,
+	pass Iter[T],
+) (rv *Layout) {
+	var (
+		w, h  = m.cells.Bounds()
+		dirs  = Points(DirectionsCardinal...)
+		open  = m.passable(pass)
+		room  = squareCover(open, w, h, max(side, 1))
+		kinds = New[CellKind](m.rc)
+	)
+
+	isOpen := func(p image.Point) bool {
+		return p.In(m.rc) && open[p.Y*w+p.X]
+	}
+
+	isRoom := func(p image.Point) bool {
+		return p.In(m.rc) && room[p.Y*w+p.X]
+	}
+
+	rv = &Layout{
+		Kinds: kinds,
+		Rooms: m.Components(dirs, func(p image.Point, _ T) bool { return isRoom(p) }),
+	}
+
+	kinds.cells.Iter(func(x, y int, _ CellKind) (next bool) {
+		p := image.Pt(x, y)
+
+		if !isOpen(p) {
+			return true
+		}
+
+		kind := CellCorridor
+
+		if isRoom(p) {
+			kind = CellRoom
+		} else {
+			var near, rooms int
+
+			for _, d := range dirs {
+				if n := p.Add(d); isOpen(n) {
+					near++
+
+					if isRoom(n) {
+						rooms++
+					}
+				}
+			}
+
+			switch {
+			case rooms > 0:
+				kind = CellDoor
+			case near <= 1:
+				kind = CellDeadEnd
+			}
+		}
+
+		kinds.cells.Set(x, y, kind)
+
+		return true
+	})
+
+	rv.Links = m.roomLinks(rv, dirs, isOpen, isRoom)
+
+	return rv
+}
+
+func (m *Map[T]) roomLinks(
+	lt *Layout,
+	dirs []image.Point,
+	isOpen, isRoom func(image.Point) bool,
+) (rv [][]int) {
+	var (
+		halls = m.Components(dirs, func(p image.Point, _ T) bool { return isOpen(p) && !isRoom(p) })
+		ends  = make([][]int, len(halls.Sizes))
+	)
+
+	lt.Kinds.Iter(func(p image.Point, k CellKind) bool {
+		if k != CellDoor {
+			return true
+		}
+
+		hall := halls.Labels.MustGet(p)
+
+		lt.Rooms.Labels.Neighbours(p, dirs, func(_ image.Point, r int) bool {
+			if r != NoLabel && !slices.Contains(ends[hall], r) {
+				ends[hall] = append(ends[hall], r)
+			}
+
+			return true
+		})
+
+		return true
+	})
+
+	rv = make([][]int, len(lt.Rooms.Sizes))
+
+	for _, rooms := range ends {
+		for _, a := range rooms {
+			for _, b := range rooms {
+				if a != b && !slices.Contains(rv[a], b) {
+					rv[a] = append(rv[a], b)
+				}
+			}
+		}
+	}
+
+	for _, links := range rv {
+		slices.Sort(links)
+	}
+
+	return rv
+}
+
+// squareCover marks open cells, that are covered by full-open square with given side, using summed-area table.
+func squareCover(open []bool, w, h, side int) (rv []bool) {
+	var (
+		sw    = w + 1
+		sum   = make([]int, sw*(h+1))
+		cover = make([]int, sw*(h+1))
+		full  = side * side
+	)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0
+			if open[y*w+x] {
+				v = 1
+			}
+
+			sum[(y+1)*sw+x+1] = v + sum[y*sw+x+1] + sum[(y+1)*sw+x] - sum[y*sw+x]
+		}
+	}
+
+	for y := 0; y+side <= h; y++ {
+		for x := 0; x+side <= w; x++ {
+			y2, x2 := y+side, x+side
+
+			if sum[y2*sw+x2]-sum[y*sw+x2]-sum[y2*sw+x]+sum[y*sw+x] != full {
+				continue
+			}
+
+			// difference array, prefix-summed below
+			cover[y*sw+x]++
+			cover[y*sw+x2]--
+			cover[y2*sw+x]--
+			cover[y2*sw+x2]++
+		}
+	}
+
+	rv = make([]bool, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x > 0 {
+				cover[y*sw+x] += cover[y*sw+x-1]
+			}
+
+			if y > 0 {
+				cover[y*sw+x] += cover[(y-1)*sw+x]
+			}
+
+			if x > 0 && y > 0 {
+				cover[y*sw+x] -= cover[(y-1)*sw+x-1]
+			}
+
+			rv[y*w+x] = cover[y*sw+x] > 0
+		}
+	}
+
+	return rv
+}
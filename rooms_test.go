@@ -0,0 +1,107 @@
+package grid
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+func mapFromStrings(rows ...string) (rv *Map[bool]) {
+	rv = New[bool](image.Rect(0, 0, len(rows[0]), len(rows)))
+
+	for y, row := range rows {
+		for x, c := range row {
+			rv.Set(image.Pt(x, y), c == '#')
+		}
+	}
+
+	return rv
+}
+
+func TestMapLayout(t *testing.T) {
+	t.Parallel()
+
+	m := mapFromStrings(
+		"################",
+		"#...#####...####",
+		"#...........####",
+		"#...#####...####",
+		"##########.#####",
+		"##########.#####",
+		"#...#####...####",
+		"#...#####......#",
+		"#...#####...####",
+		"################",
+	)
+
+	lt := m.Layout(3, isFloor)
+
+	if len(lt.Rooms.Sizes) != 4 {
+		t.Fatal("rooms:", lt.Rooms.Sizes)
+	}
+
+	for i, size := range lt.Rooms.Sizes {
+		if size != 9 {
+			t.Fatalf("room %d: size %d", i, size)
+		}
+	}
+
+	if lt.Rooms.Bounds[3] != image.Rect(9, 6, 12, 9) {
+		t.Fatal("bounds:", lt.Rooms.Bounds[3])
+	}
+
+	var kinds = []struct {
+		Point image.Point
+		Kind  CellKind
+	}{
+		{Point: image.Pt(0, 0), Kind: CellBlocked},
+		{Point: image.Pt(2, 2), Kind: CellRoom},
+		{Point: image.Pt(4, 2), Kind: CellDoor},
+		{Point: image.Pt(6, 2), Kind: CellCorridor},
+		{Point: image.Pt(8, 2), Kind: CellDoor},
+		{Point: image.Pt(10, 4), Kind: CellDoor},
+		{Point: image.Pt(10, 5), Kind: CellDoor},
+		{Point: image.Pt(12, 7), Kind: CellDoor},
+		{Point: image.Pt(13, 7), Kind: CellCorridor},
+		{Point: image.Pt(14, 7), Kind: CellDeadEnd},
+	}
+
+	for _, k := range kinds {
+		if got := lt.Kinds.MustGet(k.Point); got != k.Kind {
+			t.Fatalf("%v: got %d want %d", k.Point, got, k.Kind)
+		}
+	}
+
+	links := [][]int{{1}, {0, 3}, nil, {1}}
+
+	for i := range links {
+		if !slices.Equal(lt.Links[i], links[i]) {
+			t.Fatalf("links[%d]: got %v want %v", i, lt.Links[i], links[i])
+		}
+	}
+}
+
+func TestMapLayoutSmallSide(t *testing.T) {
+	t.Parallel()
+
+	m := mapFromStrings(
+		"#####",
+		"#...#",
+		"###.#",
+		"#...#",
+		"#####",
+	)
+
+	lt := m.Layout(0, isFloor)
+
+	if len(lt.Rooms.Sizes) != 1 || lt.Rooms.Sizes[0] != 7 || len(lt.Links[0]) != 0 {
+		t.Fatal("rooms:", lt.Rooms.Sizes, lt.Links)
+	}
+
+	lt = m.Layout(2, isFloor)
+
+	if len(lt.Rooms.Sizes) != 0 || lt.Kinds.MustGet(image.Pt(1, 1)) != CellDeadEnd ||
+		lt.Kinds.MustGet(image.Pt(3, 2)) != CellCorridor {
+		t.Fatal("corridors:", lt.Rooms.Sizes)
+	}
+}
@@ -0,0 +1,347 @@
+package grid
+
+import (
+	"image"
+	"slices"
+
+	"github.com/s0rg/set"
+)
+
+// Circle iterates in-bounds cells of circle outline (by midpoint algorithm) with given center and radius.
+func (m *Map[T]) Circle(
+	center image.Point,
+	radius int,
+	iter Iter[T],
+) {
+	m.emitPoints(circlePoints(center, radius), iter)
+}
+
+// CircleFilled iterates in-bounds cells of filled circle with given center and radius, row by row.
+func (m *Map[T]) CircleFilled(
+	center image.Point,
+	radius int,
+	iter Iter[T],
+) {
+	m.emitSpans(circlePoints(center, radius), iter)
+}
+
+// Ellipse iterates in-bounds cells of axis-aligned ellipse outline (by midpoint algorithm) with given center and radii.
+func (m *Map[T]) Ellipse(
+	center image.Point,
+	rx, ry int,
+	iter Iter[T],
+) {
+	m.emitPoints(ellipsePoints(center, rx, ry), iter)
+}
+
+// EllipseFilled iterates in-bounds cells of filled axis-aligned ellipse with given center and radii, row by row.
+func (m *Map[T]) EllipseFilled(
+	center image.Point,
+	rx, ry int,
+	iter Iter[T],
+) {
+	m.emitSpans(ellipsePoints(center, rx, ry), iter)
+}
+
+// Rect iterates in-bounds cells of rectangle outline, clockwise, starting from top-left corner.
+func (m *Map[T]) Rect(
+	rc image.Rectangle,
+	iter Iter[T],
+) {
+	m.emitPoints(rectPoints(rc.Canon()), iter)
+}
+
+// RectFilled iterates in-bounds cells of filled rectangle, row by row.
+func (m *Map[T]) RectFilled(
+	rc image.Rectangle,
+	iter Iter[T],
+) {
+	rc = rc.Canon().Intersect(m.rc)
+
+	var (
+		val T
+		ok  bool
+	)
+
+	for y := rc.Min.Y; y < rc.Max.Y; y++ {
+		for x := rc.Min.X; x < rc.Max.X; x++ {
+			if val, ok = m.cells.Get(x, y); ok && !iter(image.Pt(x, y), val) {
+				return
+			}
+		}
+	}
+}
+
+// Polygon iterates in-bounds cells of closed polygon outline, edges are drawn by Bresenham's algorithm.
+func (m *Map[T]) Polygon(
+	vertices []image.Point,
+	iter Iter[T],
+) {
+	m.emitPoints(polygonPoints(vertices), iter)
+}
+
+// PolygonFilled iterates in-bounds cells of filled polygon (with its outline), row by row. Cells with centers
+// inside polygon are found by even-odd rule, so self-intersecting polygons may have holes.
+func (m *Map[T]) PolygonFilled(
+	vertices []image.Point,
+	iter Iter[T],
+) {
+	if len(vertices) == 0 {
+		return
+	}
+
+	var (
+		outline = make(set.Unordered[image.Point])
+		bounds  = image.Rectangle{Min: vertices[0], Max: vertices[0]}
+		cross   []float64
+		val     T
+		ok      bool
+	)
+
+	for _, p := range polygonPoints(vertices) {
+		outline.Add(p)
+	}
+
+	for _, v := range vertices {
+		bounds = bounds.Union(image.Rectangle{Min: v, Max: v.Add(image.Pt(1, 1))})
+	}
+
+	bounds = bounds.Intersect(m.rc)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cross = polygonCrossings(vertices, y, cross[:0])
+
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+
+			if !outline.Has(p) && !insideSpans(cross, float64(x)) {
+				continue
+			}
+
+			if val, ok = m.cells.Get(x, y); ok && !iter(p, val) {
+				return
+			}
+		}
+	}
+}
+
+// emitPoints visits unique in-bounds points in given order.
+func (m *Map[T]) emitPoints(points []image.Point, iter Iter[T]) {
+	var (
+		seen = make(set.Unordered[image.Point])
+		val  T
+		ok   bool
+	)
+
+	for _, p := range points {
+		if !seen.Add(p) {
+			continue
+		}
+
+		if val, ok = m.cells.Get(p.X, p.Y); ok && !iter(p, val) {
+			return
+		}
+	}
+}
+
+// emitSpans fills rows between leftmost and rightmost outline points for convex shapes.
+func (m *Map[T]) emitSpans(outline []image.Point, iter Iter[T]) {
+	if len(outline) == 0 {
+		return
+	}
+
+	spans := make(map[int][2]int)
+
+	for _, p := range outline {
+		s, ok := spans[p.Y]
+		if !ok {
+			s = [2]int{p.X, p.X}
+		}
+
+		spans[p.Y] = [2]int{min(s[0], p.X), max(s[1], p.X)}
+	}
+
+	rows := make([]int, 0, len(spans))
+
+	for y := range spans {
+		rows = append(rows, y)
+	}
+
+	slices.Sort(rows)
+
+	var (
+		val T
+		ok  bool
+	)
+
+	for _, y := range rows {
+		for x := spans[y][0]; x <= spans[y][1]; x++ {
+			if val, ok = m.cells.Get(x, y); ok && !iter(image.Pt(x, y), val) {
+				return
+			}
+		}
+	}
+}
+
+func circlePoints(c image.Point, r int) (rv []image.Point) {
+	const two = 2
+
+	if r < 0 {
+		return nil
+	}
+
+	for x, y, e := r, 0, 1-r; x >= y; y++ {
+		rv = append(rv,
+			c.Add(image.Pt(x, y)), c.Add(image.Pt(y, x)),
+			c.Add(image.Pt(-y, x)), c.Add(image.Pt(-x, y)),
+			c.Add(image.Pt(-x, -y)), c.Add(image.Pt(-y, -x)),
+			c.Add(image.Pt(y, -x)), c.Add(image.Pt(x, -y)),
+		)
+
+		if e < 0 {
+			e += two*(y+1) + 1
+		} else {
+			x--
+			e += two*(y+1-x) + 1
+		}
+	}
+
+	return rv
+}
+
+func ellipsePoints(c image.Point, rx, ry int) (rv []image.Point) {
+	const (
+		two     = 2
+		quarter = 0.25
+	)
+
+	switch {
+	case rx < 0 || ry < 0:
+		return nil
+	case ry == 0:
+		return rectPoints(image.Rect(c.X-rx, c.Y, c.X+rx+1, c.Y+1))
+	}
+
+	var (
+		rx2, ry2 = float64(rx * rx), float64(ry * ry)
+		x, y     = 0, ry
+		px, py   = 0.0, two * rx2 * float64(y)
+		plot     = func() {
+			rv = append(rv,
+				c.Add(image.Pt(x, y)), c.Add(image.Pt(-x, y)),
+				c.Add(image.Pt(-x, -y)), c.Add(image.Pt(x, -y)),
+			)
+		}
+	)
+
+	// region 1: slope > -1
+	for p := ry2 - rx2*float64(ry) + rx2*quarter; px < py; {
+		plot()
+
+		x++
+		px += two * ry2
+
+		if p < 0 {
+			p += ry2 + px
+		} else {
+			y--
+			py -= two * rx2
+			p += ry2 + px - py
+		}
+	}
+
+	// region 2: slope <= -1
+	fx, fy := float64(x)+half, float64(y-1)
+
+	for p := ry2*fx*fx + rx2*fy*fy - rx2*ry2; y >= 0; {
+		plot()
+
+		y--
+		py -= two * rx2
+
+		if p > 0 {
+			p += rx2 - py
+		} else {
+			x++
+			px += two * ry2
+			p += rx2 - py + px
+		}
+	}
+
+	return rv
+}
+
+func rectPoints(rc image.Rectangle) (rv []image.Point) {
+	if rc.Empty() {
+		return nil
+	}
+
+	var (
+		top, left     = rc.Min.Y, rc.Min.X
+		bottom, right = rc.Max.Y - 1, rc.Max.X - 1
+	)
+
+	for x := left; x <= right; x++ {
+		rv = append(rv, image.Pt(x, top))
+	}
+
+	for y := top + 1; y <= bottom; y++ {
+		rv = append(rv, image.Pt(right, y))
+	}
+
+	for x := right - 1; x >= left && bottom > top; x-- {
+		rv = append(rv, image.Pt(x, bottom))
+	}
+
+	for y := bottom - 1; y > top && right > left; y-- {
+		rv = append(rv, image.Pt(left, y))
+	}
+
+	return rv
+}
+
+func polygonPoints(vertices []image.Point) (rv []image.Point) {
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+
+		bresenham(a, b, func(p image.Point) bool {
+			rv = append(rv, p)
+
+			return true
+		})
+	}
+
+	return rv
+}
+
+// polygonCrossings returns sorted x-coordinates, where horizontal line crosses polygon edges.
+func polygonCrossings(vertices []image.Point, y int, rv []float64) []float64 {
+	fy := float64(y)
+
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+
+		if (a.Y > y) == (b.Y > y) {
+			continue
+		}
+
+		rv = append(rv, float64(a.X)+(fy-float64(a.Y))*float64(b.X-a.X)/float64(b.Y-a.Y))
+	}
+
+	slices.Sort(rv)
+
+	return rv
+}
+
+// insideSpans reports if x lies between pair of sorted crossings.
+func insideSpans(cross []float64, x float64) bool {
+	const pair = 2
+
+	for i := 0; i+1 < len(cross); i += pair {
+		if x >= cross[i] && x <= cross[i+1] {
+			return true
+		}
+	}
+
+	return false
+}
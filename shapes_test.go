@@ -0,0 +1,262 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func collectShape(t *testing.T, draw func(Iter[struct{}])) (rv set.Unordered[image.Point]) {
+	t.Helper()
+
+	rv = make(set.Unordered[image.Point])
+
+	draw(func(p image.Point, _ struct{}) bool {
+		if !rv.Add(p) {
+			t.Fatalf("%v visited twice", p)
+		}
+
+		return true
+	})
+
+	return rv
+}
+
+func TestMapCircle(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H   = 31, 31
+		radius = 9
+	)
+
+	var (
+		m      = New[struct{}](image.Rect(0, 0, W, H))
+		c      = image.Pt(15, 15)
+		line   = collectShape(t, func(it Iter[struct{}]) { m.Circle(c, radius, it) })
+		filled = collectShape(t, func(it Iter[struct{}]) { m.CircleFilled(c, radius, it) })
+		oval   = collectShape(t, func(it Iter[struct{}]) { m.Ellipse(c, radius, radius, it) })
+	)
+
+	for p := range line {
+		if d := DistanceEuclidean(c, p); math.Abs(d-radius) > 1 {
+			t.Fatalf("outline %v: distance %f", p, d)
+		}
+
+		if !filled.Has(p) {
+			t.Fatalf("outline %v not filled", p)
+		}
+
+		// symmetry
+		if d := p.Sub(c); !line.Has(c.Sub(d)) || !line.Has(c.Add(image.Pt(d.Y, d.X))) {
+			t.Fatalf("outline %v not symmetric", p)
+		}
+	}
+
+	for p := range filled {
+		if d := DistanceEuclidean(c, p); d > radius+half {
+			t.Fatalf("filled %v: distance %f", p, d)
+		}
+	}
+
+	m.Iter(func(p image.Point, _ struct{}) bool {
+		if DistanceEuclidean(c, p) <= radius-half && !filled.Has(p) {
+			t.Fatalf("filled %v not visited", p)
+		}
+
+		return true
+	})
+
+	if len(oval) < len(line)-8 || len(oval) > len(line)+8 {
+		t.Fatalf("ellipse: %d circle: %d", len(oval), len(line))
+	}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.Circle(c, 0, it) }); len(l) != 1 || !l.Has(c) {
+		t.Fatal("zero radius")
+	}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.CircleFilled(c, -1, it) }); len(l) != 0 {
+		t.Fatal("negative radius")
+	}
+}
+
+func TestMapEllipse(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H   = 31, 21
+		rx, ry = 12, 5
+	)
+
+	var (
+		m      = New[struct{}](image.Rect(0, 0, W, H))
+		c      = image.Pt(15, 10)
+		line   = collectShape(t, func(it Iter[struct{}]) { m.Ellipse(c, rx, ry, it) })
+		filled = collectShape(t, func(it Iter[struct{}]) { m.EllipseFilled(c, rx, ry, it) })
+		norm   = func(p image.Point) float64 {
+			d := p.Sub(c)
+
+			return math.Hypot(float64(d.X)/rx, float64(d.Y)/ry)
+		}
+	)
+
+	for _, p := range []image.Point{
+		c.Add(image.Pt(rx, 0)), c.Add(image.Pt(-rx, 0)),
+		c.Add(image.Pt(0, ry)), c.Add(image.Pt(0, -ry)),
+	} {
+		if !line.Has(p) {
+			t.Fatalf("vertex %v not visited", p)
+		}
+	}
+
+	for p := range line {
+		if n := norm(p); math.Abs(n-1) > 0.2 {
+			t.Fatalf("outline %v: norm %f", p, n)
+		}
+
+		if !filled.Has(p) {
+			t.Fatalf("outline %v not filled", p)
+		}
+	}
+
+	m.Iter(func(p image.Point, _ struct{}) bool {
+		if n := norm(p); n < 0.9 && !filled.Has(p) {
+			t.Fatalf("filled %v not visited", p)
+		}
+
+		return true
+	})
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.Ellipse(c, 3, 0, it) }); len(l) != 7 {
+		t.Fatal("flat ellipse", len(l))
+	}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.Ellipse(c, 0, 3, it) }); len(l) != 7 {
+		t.Fatal("thin ellipse", len(l))
+	}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.EllipseFilled(c, -1, 3, it) }); len(l) != 0 {
+		t.Fatal("negative radius")
+	}
+}
+
+func TestMapRect(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 10, 10
+
+	m := New[struct{}](image.Rect(0, 0, W, H))
+
+	var cases = []struct {
+		Rect           image.Rectangle
+		Outline, Fills int
+	}{
+		{Rect: image.Rect(2, 2, 6, 5), Outline: 10, Fills: 12},
+		{Rect: image.Rect(6, 5, 2, 2), Outline: 10, Fills: 12},
+		{Rect: image.Rect(2, 2, 6, 3), Outline: 4, Fills: 4},
+		{Rect: image.Rect(2, 2, 3, 6), Outline: 4, Fills: 4},
+		{Rect: image.Rect(2, 2, 2, 6), Outline: 0, Fills: 0},
+		{Rect: image.Rect(-2, -2, 3, 3), Outline: 5, Fills: 9},
+	}
+
+	for i, tc := range cases {
+		line := collectShape(t, func(it Iter[struct{}]) { m.Rect(tc.Rect, it) })
+		fill := collectShape(t, func(it Iter[struct{}]) { m.RectFilled(tc.Rect, it) })
+
+		if len(line) != tc.Outline || len(fill) != tc.Fills {
+			t.Fatalf("case[%d] failed: outline: %d fill: %d", i, len(line), len(fill))
+		}
+	}
+}
+
+func TestMapPolygon(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 24, 16
+
+	var (
+		m        = New[struct{}](image.Rect(0, 0, W, H))
+		triangle = []image.Point{image.Pt(2, 2), image.Pt(20, 4), image.Pt(8, 14)}
+		square   = []image.Point{image.Pt(3, 3), image.Pt(9, 3), image.Pt(9, 8), image.Pt(3, 8)}
+	)
+
+	line := collectShape(t, func(it Iter[struct{}]) { m.Polygon(triangle, it) })
+	fill := collectShape(t, func(it Iter[struct{}]) { m.PolygonFilled(triangle, it) })
+
+	for _, v := range triangle {
+		if !line.Has(v) || !fill.Has(v) {
+			t.Fatalf("vertex %v not visited", v)
+		}
+	}
+
+	for p := range line {
+		if !fill.Has(p) {
+			t.Fatalf("outline %v not filled", p)
+		}
+	}
+
+	if !fill.Has(image.Pt(10, 6)) || fill.Has(image.Pt(2, 10)) {
+		t.Fatal("triangle fill")
+	}
+
+	var (
+		sq   = collectShape(t, func(it Iter[struct{}]) { m.PolygonFilled(square, it) })
+		rect = collectShape(t, func(it Iter[struct{}]) { m.RectFilled(image.Rect(3, 3, 10, 9), it) })
+	)
+
+	if len(sq) != len(rect) {
+		t.Fatalf("square: %d rect: %d", len(sq), len(rect))
+	}
+
+	for p := range rect {
+		if !sq.Has(p) {
+			t.Fatalf("square %v not visited", p)
+		}
+	}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.PolygonFilled(nil, it) }); len(l) != 0 {
+		t.Fatal("empty polygon")
+	}
+
+	clipped := []image.Point{image.Pt(-5, -5), image.Pt(5, -5), image.Pt(5, 5), image.Pt(-5, 5)}
+
+	if l := collectShape(t, func(it Iter[struct{}]) { m.PolygonFilled(clipped, it) }); len(l) != 36 {
+		t.Fatal("clipped polygon", len(l))
+	}
+}
+
+func TestMapShapesBreak(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 16, 16
+
+	var (
+		m      = New[struct{}](image.Rect(0, 0, W, H))
+		c      = image.Pt(8, 8)
+		poly   = []image.Point{image.Pt(1, 1), image.Pt(10, 1), image.Pt(5, 10)}
+		shapes = []func(Iter[struct{}]){
+			func(it Iter[struct{}]) { m.Circle(c, 5, it) },
+			func(it Iter[struct{}]) { m.CircleFilled(c, 5, it) },
+			func(it Iter[struct{}]) { m.Rect(image.Rect(2, 2, 8, 8), it) },
+			func(it Iter[struct{}]) { m.RectFilled(image.Rect(2, 2, 8, 8), it) },
+			func(it Iter[struct{}]) { m.Polygon(poly, it) },
+			func(it Iter[struct{}]) { m.PolygonFilled(poly, it) },
+		}
+	)
+
+	for i, draw := range shapes {
+		var count int
+
+		draw(func(_ image.Point, _ struct{}) bool {
+			count++
+
+			return count < 3
+		})
+
+		if count != 3 {
+			t.Fatalf("shape[%d] count: %d", i, count)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package grid
+
+import (
+	"image"
+
+	"github.com/s0rg/set"
+)
+
+const quadrants = 4
+
+// slope is an exact rational slope num/den, den is always positive.
+type slope struct {
+	num, den int
+}
+
+type symmetricShadow[T any] struct {
+	m       *Map[T]
+	blocks  Iter[T]
+	cast    Cast[T]
+	seen    set.Unordered[image.Point]
+	src     image.Point
+	distMax float64
+	quad    int
+}
+
+// CastShadowSymmetric performs symmetric shadow-casting (by Albert Ford), blocks reports cells, that blocks the light,
+// cast is called exactly once for every visible cell (its result is ignored). If cell A sees cell B, then B sees A.
+func (m *Map[T]) CastShadowSymmetric(
+	src image.Point,
+	distMax float64,
+	blocks Iter[T],
+	cast Cast[T],
+) {
+	val, ok := m.cells.Get(src.X, src.Y)
+	if !ok {
+		return
+	}
+
+	s := symmetricShadow[T]{
+		m:       m,
+		src:     src,
+		distMax: distMax,
+		blocks:  blocks,
+		cast:    cast,
+		seen:    make(set.Unordered[image.Point]),
+	}
+
+	s.seen.Add(src)
+	cast(src, 0, val)
+
+	for s.quad = 0; s.quad < quadrants; s.quad++ {
+		s.scan(1, slope{num: -1, den: 1}, slope{num: 1, den: 1})
+	}
+}
+
+func (s *symmetricShadow[T]) scan(depth int, low, high slope) {
+	if float64(depth) > s.distMax {
+		return
+	}
+
+	var (
+		wall, prevWall bool
+		first          = true
+	)
+
+	for col := low.roundUp(depth); col <= high.roundDown(depth); col++ {
+		wall = s.visit(depth, col, low, high)
+
+		if !first {
+			switch {
+			case prevWall && !wall:
+				low = tileSlope(depth, col)
+			case !prevWall && wall:
+				s.scan(depth+1, low, tileSlope(depth, col))
+			}
+		}
+
+		prevWall, first = wall, false
+	}
+
+	if !first && !prevWall {
+		s.scan(depth+1, low, high)
+	}
+}
+
+// visit reveals cell (if visible) and reports if it blocks the light.
+func (s *symmetricShadow[T]) visit(depth, col int, low, high slope) (wall bool) {
+	pt := quadrantPoint(s.src, s.quad, depth, col)
+
+	val, ok := s.m.cells.Get(pt.X, pt.Y)
+	if !ok {
+		return true
+	}
+
+	wall = s.blocks(pt, val)
+
+	if !wall && !(low.lessEq(col, depth) && high.greaterEq(col, depth)) {
+		return false
+	}
+
+	if dist := DistanceEuclidean(s.src, pt); dist <= s.distMax && s.seen.Add(pt) {
+		s.cast(pt, dist, val)
+	}
+
+	return wall
+}
+
+// tileSlope returns slope of the left tile edge.
+func tileSlope(depth, col int) slope {
+	const two = 2
+
+	return slope{num: two*col - 1, den: two * depth}
+}
+
+// roundUp returns depth * s, rounded with ties up.
+func (s slope) roundUp(depth int) int {
+	const two = 2
+
+	return floorDiv(two*depth*s.num+s.den, two*s.den)
+}
+
+// roundDown returns depth * s, rounded with ties down.
+func (s slope) roundDown(depth int) int {
+	const two = 2
+
+	return -floorDiv(s.den-two*depth*s.num, two*s.den)
+}
+
+// lessEq reports if depth * s <= col.
+func (s slope) lessEq(col, depth int) bool {
+	return depth*s.num <= col*s.den
+}
+
+// greaterEq reports if depth * s >= col.
+func (s slope) greaterEq(col, depth int) bool {
+	return depth*s.num >= col*s.den
+}
+
+func quadrantPoint(p image.Point, quad, depth, col int) image.Point {
+	switch quad {
+	case 0: // north
+		return p.Add(image.Pt(col, -depth))
+	case 1: // east
+		return p.Add(image.Pt(depth, col))
+	case 2: // south
+		return p.Add(image.Pt(col, depth))
+	}
+
+	// west
+	return p.Add(image.Pt(-depth, col))
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
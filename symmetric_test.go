@@ -0,0 +1,159 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func randomWalls(seed uint64, w, h int, density float64) *Map[bool] {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	m := New[bool](image.Rect(0, 0, w, h))
+
+	m.Iter(func(p image.Point, _ bool) bool {
+		m.Set(p, rng.Float64() < density)
+
+		return true
+	})
+
+	return m
+}
+
+func isWall(_ image.Point, wall bool) bool {
+	return wall
+}
+
+func TestMapShadowSymmetric(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 5, 5
+
+	var (
+		src  = image.Pt(1, 1)
+		seen = make(set.Unordered[image.Point])
+		m    = New[bool](image.Rect(0, 0, W, H))
+	)
+
+	m.Iter(func(p image.Point, _ bool) (next bool) {
+		m.Set(p, p.X == 0 || p.X == W-1 || p.Y == 0 || p.Y == H-1)
+
+		return true
+	})
+
+	m.Set(image.Pt(2, 1), true)
+	m.Set(image.Pt(2, 2), true)
+	m.Set(image.Pt(2, 3), true)
+
+	m.CastShadowSymmetric(src, 3.0, isWall, func(p image.Point, _ float64, _ bool) bool {
+		if !seen.Add(p) {
+			t.Fatalf("seen twice: %v", p)
+		}
+
+		return true
+	})
+
+	if seen.Has(image.Pt(3, 1)) {
+		t.Fail()
+	}
+
+	if !seen.Has(image.Pt(2, 2)) || !seen.Has(image.Pt(1, 3)) {
+		t.Fail()
+	}
+
+	seen = make(set.Unordered[image.Point])
+
+	m.CastShadowSymmetric(image.Pt(6, 1), 3.0, isWall, func(p image.Point, _ float64, _ bool) bool {
+		seen.Add(p)
+
+		return true
+	})
+
+	if len(seen) != 0 {
+		t.Fail()
+	}
+}
+
+func TestMapShadowSymmetricDistance(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 21, 21
+		dist = 5.0
+	)
+
+	var (
+		src = image.Pt(10, 10)
+		m   = New[bool](image.Rect(0, 0, W, H))
+		cnt int
+	)
+
+	m.CastShadowSymmetric(src, dist, isWall, func(p image.Point, d float64, _ bool) bool {
+		if d > dist || d != DistanceEuclidean(src, p) {
+			t.Fatalf("invalid distance for %v: %f", p, d)
+		}
+
+		cnt++
+
+		return true
+	})
+
+	var want int
+
+	m.Iter(func(p image.Point, _ bool) bool {
+		if DistanceEuclidean(src, p) <= dist {
+			want++
+		}
+
+		return true
+	})
+
+	if cnt != want {
+		t.Fatalf("want: %d got: %d", want, cnt)
+	}
+}
+
+func TestMapShadowSymmetricIsSymmetric(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H    = 24, 16
+		dist    = 30.0
+		seeds   = 5
+		density = 0.25
+	)
+
+	for seed := uint64(1); seed <= seeds; seed++ {
+		m := randomWalls(seed, W, H, density)
+		fov := make(map[image.Point]set.Unordered[image.Point])
+
+		m.Iter(func(src image.Point, wall bool) bool {
+			if wall {
+				return true
+			}
+
+			seen := make(set.Unordered[image.Point])
+
+			m.CastShadowSymmetric(src, dist, isWall, func(p image.Point, _ float64, w bool) bool {
+				if !w {
+					seen.Add(p)
+				}
+
+				return true
+			})
+
+			fov[src] = seen
+
+			return true
+		})
+
+		for a, seen := range fov {
+			for b := range seen {
+				if !fov[b].Has(a) {
+					t.Fatalf("seed %d: %v sees %v, but not vice versa", seed, a, b)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package grid
+
+import (
+	"image"
+	"math"
+)
+
+// DistanceTransformEuclidean calculates exact [DistanceEuclidean] from every cell to the nearest one, that matches
+// given predicate, in linear time (by Felzenszwalb-Huttenlocher algorithm). Matched cells have zero distance,
+// if no cells matched, all distances are +Inf.
+func (m *Map[T]) DistanceTransformEuclidean(match Iter[T]) (rv *Map[float64]) {
+	var (
+		w, h = m.cells.Bounds()
+		n    = max(w, h)
+		big  = float64((w + h) * (w + h)) // exceeds any squared distance within map
+		f    = make([]float64, n)
+		d    = make([]float64, n)
+		v    = make([]int, n)
+		z    = make([]float64, n+1)
+	)
+
+	rv = m.transformInit(match, big)
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = rv.MustGet(image.Pt(x, y))
+		}
+
+		edt1d(f[:h], d[:h], v, z)
+
+		for y := 0; y < h; y++ {
+			rv.cells.Set(x, y, d[y])
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			f[x] = rv.MustGet(image.Pt(x, y))
+		}
+
+		edt1d(f[:w], d[:w], v, z)
+
+		for x := 0; x < w; x++ {
+			val := math.Inf(1)
+			if d[x] < big {
+				val = math.Sqrt(d[x])
+			}
+
+			rv.cells.Set(x, y, val)
+		}
+	}
+
+	return rv
+}
+
+// DistanceTransformManhattan calculates exact [DistanceManhattan] from every cell to the nearest one, that matches
+// given predicate, in linear time (by two-pass chamfer). See [Map.DistanceTransformEuclidean] for details.
+func (m *Map[T]) DistanceTransformManhattan(match Iter[T]) (rv *Map[float64]) {
+	return m.chamfer(match, []image.Point{{X: -1}, {Y: -1}})
+}
+
+// DistanceTransformChebyshev calculates exact [DistanceChebyshev] from every cell to the nearest one, that matches
+// given predicate, in linear time (by two-pass chamfer). See [Map.DistanceTransformEuclidean] for details.
+func (m *Map[T]) DistanceTransformChebyshev(match Iter[T]) (rv *Map[float64]) {
+	return m.chamfer(match, []image.Point{{X: -1}, {X: -1, Y: -1}, {Y: -1}, {X: 1, Y: -1}})
+}
+
+// transformInit creates distance grid with zeroes for matched cells and given value for others.
+func (m *Map[T]) transformInit(match Iter[T], other float64) (rv *Map[float64]) {
+	rv = New[float64](m.rc)
+
+	m.cells.Iter(func(x, y int, v T) (next bool) {
+		if !match(image.Pt(x, y), v) {
+			rv.cells.Set(x, y, other)
+		}
+
+		return true
+	})
+
+	return rv
+}
+
+// chamfer performs forward raster pass with given (already visited) neighbours with unit step, then backward pass
+// with opposite ones.
+func (m *Map[T]) chamfer(match Iter[T], mask []image.Point) (rv *Map[float64]) {
+	var (
+		w, h = m.cells.Bounds()
+		back = make([]image.Point, len(mask))
+	)
+
+	for i, d := range mask {
+		back[i] = image.Pt(-d.X, -d.Y)
+	}
+
+	rv = m.transformInit(match, math.Inf(1))
+
+	relax := func(x, y int, dirs []image.Point) {
+		p := image.Pt(x, y)
+		best := rv.MustGet(p)
+
+		for _, d := range dirs {
+			if v, ok := rv.cells.Get(x+d.X, y+d.Y); ok {
+				best = math.Min(best, v+one)
+			}
+		}
+
+		rv.cells.Set(x, y, best)
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			relax(x, y, mask)
+		}
+	}
+
+	for y := h - 1; y >= 0; y-- {
+		for x := w - 1; x >= 0; x-- {
+			relax(x, y, back)
+		}
+	}
+
+	return rv
+}
+
+// edt1d calculates squared distance transform of sampled function f into d, v and z are scratch buffers.
+func edt1d(f, d []float64, v []int, z []float64) {
+	const two = 2
+
+	var (
+		k   int
+		inf = math.Inf(1)
+	)
+
+	parabola := func(q, p int) float64 {
+		fq, fp := f[q]+float64(q*q), f[p]+float64(p*p)
+
+		return (fq - fp) / float64(two*(q-p))
+	}
+
+	v[0], z[0], z[1] = 0, -inf, inf
+
+	for q := 1; q < len(f); q++ {
+		s := parabola(q, v[k])
+
+		for s <= z[k] {
+			k--
+			s = parabola(q, v[k])
+		}
+
+		k++
+		v[k], z[k], z[k+1] = q, s, inf
+	}
+
+	k = 0
+
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+
+		dq := float64(q - v[k])
+		d[q] = dq*dq + f[v[k]]
+	}
+}
@@ -0,0 +1,101 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestMapDistanceTransform(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 23, 17
+
+	var cases = []struct {
+		Transform func(*Map[bool]) *Map[float64]
+		Dist      Distance
+	}{
+		{
+			Transform: func(m *Map[bool]) *Map[float64] { return m.DistanceTransformEuclidean(isWall) },
+			Dist:      DistanceEuclidean,
+		},
+		{
+			Transform: func(m *Map[bool]) *Map[float64] { return m.DistanceTransformManhattan(isWall) },
+			Dist:      DistanceManhattan,
+		},
+		{
+			Transform: func(m *Map[bool]) *Map[float64] { return m.DistanceTransformChebyshev(isWall) },
+			Dist:      DistanceChebyshev,
+		},
+	}
+
+	for seed := uint64(1); seed <= 4; seed++ {
+		for _, density := range []float64{0.02, 0.2} {
+			m := randomWalls(seed, W, H, density)
+
+			for i, tc := range cases {
+				rv := tc.Transform(m)
+
+				if rv.Rectangle() != m.Rectangle() {
+					t.Fatalf("case[%d]: bounds", i)
+				}
+
+				rv.Iter(func(p image.Point, got float64) bool {
+					want := math.Inf(1)
+
+					m.Iter(func(q image.Point, wall bool) bool {
+						if wall {
+							want = math.Min(want, tc.Dist(p, q))
+						}
+
+						return true
+					})
+
+					if math.Abs(got-want) > 1e-9 {
+						t.Fatalf("seed %d case[%d] %v: got %f want %f", seed, i, p, got, want)
+					}
+
+					return true
+				})
+			}
+		}
+	}
+}
+
+func TestMapDistanceTransformEmpty(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 7, 5
+
+	m := New[bool](image.Rect(0, 0, W, H))
+
+	for _, rv := range []*Map[float64]{
+		m.DistanceTransformEuclidean(isWall),
+		m.DistanceTransformManhattan(isWall),
+		m.DistanceTransformChebyshev(isWall),
+	} {
+		rv.Iter(func(p image.Point, v float64) bool {
+			if !math.IsInf(v, 1) {
+				t.Fatalf("%v: %f", p, v)
+			}
+
+			return true
+		})
+	}
+
+	m.Fill(func() bool { return true })
+
+	for _, rv := range []*Map[float64]{
+		m.DistanceTransformEuclidean(isWall),
+		m.DistanceTransformManhattan(isWall),
+		m.DistanceTransformChebyshev(isWall),
+	} {
+		rv.Iter(func(p image.Point, v float64) bool {
+			if v != 0 {
+				t.Fatalf("%v: %f", p, v)
+			}
+
+			return true
+		})
+	}
+}
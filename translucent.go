@@ -0,0 +1,79 @@
+package grid
+
+import (
+	"image"
+	"math"
+)
+
+// Opacity is a partial-opacity callback, it returns fraction of visibility, that cell blocks:
+// zero for transparent cells, one for opaque ones.
+type Opacity[T any] func(image.Point, T) float64
+
+// Sight is a partial visibility callback, it receives distance and remaining visibility amount (in range (0, 1]).
+type Sight[T any] func(p image.Point, dist, amount float64, val T) bool
+
+type sight struct {
+	Point  image.Point
+	Dist   float64
+	Amount float64
+}
+
+// CastTranslucent iterates visible cells within given distance, where cells may be partially opaque (smoke, foliage,
+// glass). Visibility attenuates along rays, cell is reported with the best remaining visibility amount among all rays,
+// that reach it (opacity of cell itself is not taken into account). Opacity is requested once for every reached
+// cell, cells are reported (source cell first) in order of discovery, see [Map.LineOfSight] for rays order.
+func (m *Map[T]) CastTranslucent(
+	src image.Point,
+	distMax float64,
+	opacity Opacity[T],
+	visit Sight[T],
+) {
+	val, ok := m.cells.Get(src.X, src.Y)
+	if !ok {
+		return
+	}
+
+	var (
+		index   = make(map[image.Point]int)
+		opaque  = make(map[image.Point]float64)
+		visible []sight
+	)
+
+	m.fan(src, 0, maxDegrees, distMax, func() func(*RayHit) bool {
+		amount := one
+
+		return func(h *RayHit) bool {
+			v, ok := m.cells.Get(h.Cell.X, h.Cell.Y)
+			if !ok {
+				return false
+			}
+
+			if i, found := index[h.Cell]; found {
+				visible[i].Amount = math.Max(visible[i].Amount, amount)
+			} else {
+				index[h.Cell] = len(visible)
+				visible = append(visible, sight{Point: h.Cell, Dist: h.Dist, Amount: amount})
+			}
+
+			o, found := opaque[h.Cell]
+			if !found {
+				o = math.Min(one, math.Max(0, opacity(h.Cell, v)))
+				opaque[h.Cell] = o
+			}
+
+			amount *= one - o
+
+			return amount > 0
+		}
+	})
+
+	if !visit(src, 0, one, val) {
+		return
+	}
+
+	for _, s := range visible {
+		if !visit(s.Point, s.Dist, s.Amount, m.MustGet(s.Point)) {
+			break
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestMapCastTranslucent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H  = 21, 21
+		dist  = 8.0
+		smoke = 1
+		wall  = 2
+	)
+
+	var (
+		m       = New[int](image.Rect(0, 0, W, H))
+		src     = image.Pt(10, 10)
+		amounts = make(map[image.Point]float64)
+		opacity = func(_ image.Point, v int) float64 {
+			switch v {
+			case smoke:
+				return 0.5
+			case wall:
+				return 2 // clamped to 1
+			}
+
+			return 0
+		}
+	)
+
+	for y := 0; y < H; y++ {
+		m.Set(image.Pt(12, y), smoke)
+		m.Set(image.Pt(13, y), smoke)
+		m.Set(image.Pt(7, y), wall)
+	}
+
+	var first = true
+
+	m.CastTranslucent(src, dist, opacity, func(p image.Point, d, a float64, _ int) bool {
+		if first && !p.Eq(src) {
+			t.Fatal("source is not first")
+		}
+
+		if _, ok := amounts[p]; ok {
+			t.Fatalf("%v seen twice", p)
+		}
+
+		if d > dist {
+			t.Fatalf("%v too far", p)
+		}
+
+		amounts[p], first = a, false
+
+		return true
+	})
+
+	var cases = []struct {
+		Point  image.Point
+		Amount float64
+	}{
+		{Point: image.Pt(11, 10), Amount: 1},
+		{Point: image.Pt(12, 10), Amount: 1},
+		{Point: image.Pt(13, 10), Amount: 0.5},
+		{Point: image.Pt(15, 10), Amount: 0.25},
+		{Point: image.Pt(7, 10), Amount: 1},
+		{Point: image.Pt(6, 10), Amount: 0},
+	}
+
+	for i, tc := range cases {
+		if a := amounts[tc.Point]; math.Abs(a-tc.Amount) > 1e-9 {
+			t.Fatalf("case[%d] %v want: %f got: %f", i, tc.Point, tc.Amount, a)
+		}
+	}
+
+	var count int
+
+	m.CastTranslucent(src, dist*3, opacity, func(_ image.Point, _, _ float64, _ int) bool {
+		count++
+
+		return count < 3
+	})
+
+	if count != 3 {
+		t.Fail()
+	}
+
+	m.CastTranslucent(src, dist, opacity, func(_ image.Point, _, _ float64, _ int) bool {
+		count++
+
+		return false
+	})
+
+	if count != 4 {
+		t.Fail()
+	}
+
+	m.CastTranslucent(image.Pt(W, H), dist, opacity, func(_ image.Point, _, _ float64, _ int) bool {
+		t.Fatal("out-of-bounds source")
+
+		return true
+	})
+}
@@ -0,0 +1,113 @@
+package grid
+
+import "image"
+
+const wordBits = 64
+
+// Visibility is a precomputed cell-to-cell visibility within given radius, it holds visibility bitset for every
+// map cell. Visibility is symmetric (see [Map.CastShadowSymmetric]), cells changed via [Map.Set] (or [Map.Fill])
+// are recomputed on demand.
+type Visibility[T any] struct {
+	m      *Map[T]
+	opaque Iter[T]
+	bits   []uint64
+	dirty  []bool
+	radius int
+	side   int
+	words  int
+	width  int
+}
+
+// NewVisibility builds [Visibility] for given map and radius, opaque reports cells, that blocks the sight.
+func NewVisibility[T any](m *Map[T], radius int, opaque Iter[T]) (rv *Visibility[T]) {
+	const two = 2
+
+	var (
+		w, h  = m.cells.Bounds()
+		side  = two*radius + 1
+		words = (side*side + wordBits - 1) / wordBits
+	)
+
+	rv = &Visibility[T]{
+		m:      m,
+		opaque: opaque,
+		radius: radius,
+		side:   side,
+		words:  words,
+		width:  w,
+		bits:   make([]uint64, w*h*words),
+		dirty:  make([]bool, w*h),
+	}
+
+	rv.reset()
+
+	m.watch(rv)
+
+	return rv
+}
+
+// Visible reports if cell b is visible from cell a.
+func (v *Visibility[T]) Visible(a, b image.Point) bool {
+	if !a.In(v.m.rc) || !b.In(v.m.rc) {
+		return false
+	}
+
+	d := b.Sub(a)
+
+	if abs(d.X) > v.radius || abs(d.Y) > v.radius {
+		return false
+	}
+
+	idx := a.Y*v.width + a.X
+
+	if v.dirty[idx] {
+		v.compute(a, idx)
+	}
+
+	bit := (d.Y+v.radius)*v.side + (d.X + v.radius)
+
+	return v.bits[idx*v.words+bit/wordBits]&(1<<(bit%wordBits)) != 0
+}
+
+// Invalidate marks all cells, that may see given point, for re-calculation.
+// Use it if cell was modified in-place, not via [Map.Set].
+func (v *Visibility[T]) Invalidate(p image.Point) {
+	rc := image.Rect(p.X-v.radius, p.Y-v.radius, p.X+v.radius+1, p.Y+v.radius+1).Intersect(v.m.rc)
+
+	for y := rc.Min.Y; y < rc.Max.Y; y++ {
+		for x := rc.Min.X; x < rc.Max.X; x++ {
+			v.dirty[y*v.width+x] = true
+		}
+	}
+}
+
+// Close detaches visibility from its map.
+func (v *Visibility[T]) Close() {
+	v.m.unwatch(v)
+}
+
+func (v *Visibility[T]) compute(src image.Point, idx int) {
+	row := v.bits[idx*v.words : (idx+1)*v.words]
+
+	clear(row)
+
+	v.m.CastShadowSymmetric(src, float64(v.radius), v.opaque, func(p image.Point, _ float64, _ T) bool {
+		d := p.Sub(src)
+		bit := (d.Y+v.radius)*v.side + (d.X + v.radius)
+		row[bit/wordBits] |= 1 << (bit % wordBits)
+
+		return true
+	})
+
+	v.dirty[idx] = false
+}
+
+func (v *Visibility[T]) changed(p image.Point) {
+	v.Invalidate(p)
+}
+
+func (v *Visibility[T]) reset() {
+	for i := range v.dirty {
+		v.dirty[i] = true
+	}
+}
@@ -0,0 +1,120 @@
+package grid
+
+import (
+	"image"
+	"testing"
+
+	"github.com/s0rg/set"
+)
+
+func checkVisibility(t *testing.T, m *Map[bool], v *Visibility[bool], radius int) {
+	t.Helper()
+
+	m.Iter(func(a image.Point, _ bool) bool {
+		seen := make(set.Unordered[image.Point])
+
+		m.CastShadowSymmetric(a, float64(radius), isWall, func(p image.Point, _ float64, _ bool) bool {
+			seen.Add(p)
+
+			return true
+		})
+
+		m.Iter(func(b image.Point, _ bool) bool {
+			if v.Visible(a, b) != seen.Has(b) {
+				t.Fatalf("%v -> %v mismatch", a, b)
+			}
+
+			return true
+		})
+
+		return true
+	})
+}
+
+func TestVisibility(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H   = 16, 12
+		radius = 5
+	)
+
+	m := randomWalls(7, W, H, 0.2)
+	v := NewVisibility(m, radius, isWall)
+
+	checkVisibility(t, m, v, radius)
+
+	// changes via Set
+	m.Set(image.Pt(5, 5), true)
+	m.Set(image.Pt(6, 6), false)
+
+	checkVisibility(t, m, v, radius)
+
+	if v.Visible(image.Pt(-1, 0), image.Pt(0, 0)) || v.Visible(image.Pt(0, 0), image.Pt(W, 0)) {
+		t.Fatal("out-of-bounds")
+	}
+
+	v.Close()
+}
+
+func TestVisibilityInvalidate(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 9, 3
+
+	var (
+		m = New[*bool](image.Rect(0, 0, W, H))
+		a = image.Pt(0, 1)
+		b = image.Pt(8, 1)
+		c = image.Pt(4, 1)
+		v = NewVisibility(m, W, func(_ image.Point, w *bool) bool { return *w })
+	)
+
+	m.Fill(func() *bool { return new(bool) })
+
+	if !v.Visible(a, b) || !v.Visible(b, a) {
+		t.Fatal("step 1")
+	}
+
+	for y := 0; y < H; y++ {
+		*m.MustGet(image.Pt(c.X, y)) = true
+	}
+
+	if !v.Visible(a, b) {
+		t.Fatal("step 2 - in-place change is not tracked")
+	}
+
+	for y := 0; y < H; y++ {
+		v.Invalidate(image.Pt(c.X, y))
+	}
+
+	if v.Visible(a, b) || v.Visible(b, a) || !v.Visible(a, c) {
+		t.Fatal("step 3")
+	}
+
+	v = NewVisibility(m, 2, func(_ image.Point, w *bool) bool { return *w })
+
+	if !v.Visible(a, a.Add(image.Pt(2, 0))) || v.Visible(a, a.Add(image.Pt(3, 0))) {
+		t.Fatal("step 4 - radius")
+	}
+}
+
+func TestVisibilityFill(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H   = 10, 8
+		radius = 4
+	)
+
+	var (
+		m = New[bool](image.Rect(0, 0, W, H))
+		v = NewVisibility(m, radius, isWall)
+	)
+
+	checkVisibility(t, m, v, radius)
+
+	m.Fill(func() bool { return true })
+
+	checkVisibility(t, m, v, radius)
+}
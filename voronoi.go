@@ -0,0 +1,175 @@
+package grid
+
+import (
+	"image"
+	"math"
+
+	"github.com/zyedidia/generic/heap"
+)
+
+// Partition is a map partitioning into regions, one for every seed.
+type Partition struct {
+	// Labels holds seed index for every cell, or [NoLabel] for not reached (or not passable) ones.
+	Labels *Map[int]
+	// Boundaries holds cells of every region (in row-major order), that have neighbours from other regions.
+	Boundaries [][]image.Point
+}
+
+type voronoiStep struct {
+	Point image.Point
+	Dist  float64
+	Label int
+}
+
+// VoronoiPath assigns every passable cell to the nearest (by travel distance) seed, like [Map.DijkstraMap] does
+// for multiple targets, but keeps track of the nearest one. Travel goes in given directions, every step costs
+// distance between cells, ties are resolved in favour of seed with lower index. Impassable or out-of-bounds seeds
+// have empty regions.
+func (m *Map[T]) VoronoiPath(
+	seeds, dirs []image.Point,
+	dist Distance,
+	pass Iter[T],
+) (rv *Partition) {
+	var (
+		w, _  = m.cells.Bounds()
+		open  = m.passable(pass)
+		best  = make([]float64, len(open))
+		queue = heap.New(func(a, b voronoiStep) bool {
+			if a.Dist != b.Dist {
+				return a.Dist < b.Dist
+			}
+
+			return a.Label < b.Label
+		})
+	)
+
+	rv = m.newPartition(seeds)
+
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+
+	for i, s := range seeds {
+		if !s.In(m.rc) || !open[s.Y*w+s.X] || best[s.Y*w+s.X] == 0 {
+			continue
+		}
+
+		best[s.Y*w+s.X] = 0
+		rv.Labels.cells.Set(s.X, s.Y, i)
+		queue.Push(voronoiStep{Point: s, Label: i})
+	}
+
+	for queue.Size() > 0 {
+		cur, _ := queue.Pop()
+
+		if cur.Dist > best[cur.Point.Y*w+cur.Point.X] || rv.Labels.MustGet(cur.Point) != cur.Label {
+			continue
+		}
+
+		m.Neighbours(cur.Point, dirs, func(p image.Point, _ T) bool {
+			idx := p.Y*w + p.X
+
+			if !open[idx] {
+				return true
+			}
+
+			nd := cur.Dist + dist(cur.Point, p)
+
+			if nd < best[idx] || (nd == best[idx] && cur.Label < rv.Labels.MustGet(p)) {
+				best[idx] = nd
+				rv.Labels.cells.Set(p.X, p.Y, cur.Label)
+				queue.Push(voronoiStep{Point: p, Dist: nd, Label: cur.Label})
+			}
+
+			return true
+		})
+	}
+
+	rv.boundaries(dirs)
+
+	return rv
+}
+
+// VoronoiDistance assigns every passable cell to the nearest (by straight distance, walls are ignored) seed,
+// ties are resolved in favour of seed with lower index. Directions are used to find region boundaries only,
+// see [Map.VoronoiPath] for details.
+func (m *Map[T]) VoronoiDistance(
+	seeds, dirs []image.Point,
+	dist Distance,
+	pass Iter[T],
+) (rv *Partition) {
+	valid := make([]bool, len(seeds))
+
+	for i, s := range seeds {
+		if v, ok := m.cells.Get(s.X, s.Y); ok {
+			valid[i] = pass(s, v)
+		}
+	}
+
+	rv = m.newPartition(seeds)
+
+	m.cells.Iter(func(x, y int, v T) (next bool) {
+		p := image.Pt(x, y)
+
+		if !pass(p, v) {
+			return true
+		}
+
+		var (
+			label = NoLabel
+			near  = math.Inf(1)
+		)
+
+		for i, s := range seeds {
+			if d := dist(p, s); valid[i] && d < near {
+				label, near = i, d
+			}
+		}
+
+		rv.Labels.cells.Set(x, y, label)
+
+		return true
+	})
+
+	rv.boundaries(dirs)
+
+	return rv
+}
+
+func (m *Map[T]) newPartition(seeds []image.Point) (rv *Partition) {
+	rv = &Partition{
+		Labels:     New[int](m.rc),
+		Boundaries: make([][]image.Point, len(seeds)),
+	}
+
+	rv.Labels.Fill(func() int {
+		return NoLabel
+	})
+
+	return rv
+}
+
+func (pt *Partition) boundaries(dirs []image.Point) {
+	w, h := pt.Labels.Bounds()
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := image.Pt(x, y)
+
+			label := pt.Labels.MustGet(p)
+			if label == NoLabel {
+				continue
+			}
+
+			pt.Labels.Neighbours(p, dirs, func(_ image.Point, l int) bool {
+				if l == NoLabel || l == label {
+					return true
+				}
+
+				pt.Boundaries[label] = append(pt.Boundaries[label], p)
+
+				return false
+			})
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package grid
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// bfsSteps returns number of steps from source to every reachable floor cell.
+func bfsSteps(m *Map[bool], src image.Point, dirs []image.Point) (rv map[image.Point]int) {
+	rv = make(map[image.Point]int)
+
+	if v, ok := m.Get(src); !ok || v {
+		return rv
+	}
+
+	rv[src] = 0
+
+	for queue := []image.Point{src}; len(queue) > 0; queue = queue[1:] {
+		cur := queue[0]
+
+		m.Neighbours(cur, dirs, func(p image.Point, wall bool) bool {
+			if _, ok := rv[p]; !ok && !wall {
+				rv[p] = rv[cur] + 1
+				queue = append(queue, p)
+			}
+
+			return true
+		})
+	}
+
+	return rv
+}
+
+func checkBoundaries(t *testing.T, pt *Partition, dirs []image.Point) {
+	t.Helper()
+
+	border := make(map[image.Point]bool)
+
+	for label, cells := range pt.Boundaries {
+		for _, p := range cells {
+			if pt.Labels.MustGet(p) != label {
+				t.Fatalf("boundary %v: label %d", p, label)
+			}
+
+			border[p] = true
+		}
+	}
+
+	pt.Labels.Iter(func(p image.Point, label int) bool {
+		if label == NoLabel {
+			return true
+		}
+
+		var other bool
+
+		pt.Labels.Neighbours(p, dirs, func(_ image.Point, l int) bool {
+			other = other || (l != NoLabel && l != label)
+
+			return true
+		})
+
+		if other != border[p] {
+			t.Fatalf("boundary %v: %t", p, other)
+		}
+
+		return true
+	})
+}
+
+func TestMapVoronoiPath(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 30, 20
+
+	var cases = []struct {
+		Dirs []image.Point
+		Dist Distance
+	}{
+		{Dirs: Points(DirectionsCardinal...), Dist: DistanceManhattan},
+		{Dirs: Points(DirectionsALL...), Dist: DistanceChebyshev},
+	}
+
+	for seed := uint64(1); seed <= 4; seed++ {
+		var (
+			m     = randomWalls(seed, W, H, 0.3)
+			seeds = []image.Point{{X: 2, Y: 2}, {X: 25, Y: 3}, {X: 14, Y: 10}, {X: 4, Y: 17}, {X: 27, Y: 18}}
+		)
+
+		for _, s := range seeds {
+			m.Set(s, false)
+		}
+
+		for i, tc := range cases {
+			var (
+				pt    = m.VoronoiPath(seeds, tc.Dirs, tc.Dist, isFloor)
+				steps = make([]map[image.Point]int, len(seeds))
+			)
+
+			for j, s := range seeds {
+				steps[j] = bfsSteps(m, s, tc.Dirs)
+			}
+
+			pt.Labels.Iter(func(p image.Point, label int) bool {
+				want, near := NoLabel, math.MaxInt
+
+				for j := range seeds {
+					if d, ok := steps[j][p]; ok && d < near {
+						want, near = j, d
+					}
+				}
+
+				if label != want {
+					t.Fatalf("seed %d case[%d] %v: got %d want %d", seed, i, p, label, want)
+				}
+
+				return true
+			})
+
+			checkBoundaries(t, pt, tc.Dirs)
+		}
+	}
+}
+
+func TestMapVoronoiWalls(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 11, 5
+
+	var (
+		m     = New[bool](image.Rect(0, 0, W, H))
+		dirs  = Points(DirectionsCardinal...)
+		seeds = []image.Point{{X: 1, Y: 1}, {X: 10, Y: 4}, {X: -1, Y: 0}, {X: 5, Y: 1}, {X: 1, Y: 1}}
+		cell  = image.Pt(6, 0)
+	)
+
+	// wall with a door at bottom
+	for y := 0; y < H-1; y++ {
+		m.Set(image.Pt(5, y), true)
+	}
+
+	path := m.VoronoiPath(seeds, dirs, DistanceManhattan, isFloor)
+	straight := m.VoronoiDistance(seeds, dirs, DistanceManhattan, isFloor)
+
+	if path.Labels.MustGet(cell) != 1 || straight.Labels.MustGet(cell) != 0 {
+		t.Fatal("labels:", path.Labels.MustGet(cell), straight.Labels.MustGet(cell))
+	}
+
+	// invalid and duplicate seeds
+	for _, label := range []int{2, 3, 4} {
+		if len(path.Boundaries[label]) != 0 {
+			t.Fatal("path boundaries:", label)
+		}
+
+		path.Labels.Iter(func(p image.Point, l int) bool {
+			if l == label {
+				t.Fatalf("path: %v has label %d", p, l)
+			}
+
+			return true
+		})
+	}
+
+	// diagonal steps
+	diag := m.VoronoiPath(seeds, Points(DirectionsALL...), DistanceEuclidean, isFloor)
+	if diag.Labels.MustGet(cell) != 1 {
+		t.Fatal("diagonal labels:", diag.Labels.MustGet(cell))
+	}
+
+	if straight.Labels.MustGet(image.Pt(5, 0)) != NoLabel {
+		t.Fatal("wall labelled")
+	}
+
+	if len(path.Boundaries[0]) != 1 || path.Boundaries[0][0] != image.Pt(4, 4) {
+		t.Fatal("door boundary:", path.Boundaries[0])
+	}
+}
+
+func TestMapVoronoiDistance(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 30, 20
+
+	var (
+		m     = randomWalls(3, W, H, 0.3)
+		dirs  = Points(DirectionsALL...)
+		seeds = []image.Point{{X: 2, Y: 2}, {X: 25, Y: 3}, {X: 14, Y: 10}, {X: 4, Y: 17}, {X: 27, Y: 18}}
+	)
+
+	for _, s := range seeds {
+		m.Set(s, false)
+	}
+
+	for _, dist := range []Distance{DistanceEuclidean, DistanceManhattan, DistanceChebyshev} {
+		pt := m.VoronoiDistance(seeds, dirs, dist, isFloor)
+
+		m.Iter(func(p image.Point, wall bool) bool {
+			want := NoLabel
+
+			if !wall {
+				near := math.Inf(1)
+
+				for j, s := range seeds {
+					if d := dist(p, s); d < near {
+						want, near = j, d
+					}
+				}
+			}
+
+			if got := pt.Labels.MustGet(p); got != want {
+				t.Fatalf("%v: got %d want %d", p, got, want)
+			}
+
+			return true
+		})
+
+		checkBoundaries(t, pt, dirs)
+	}
+}
@@ -0,0 +1,137 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+)
+
+// WalkConfig holds settings for [Map.RandomWalk], zero values are replaced with defaults.
+type WalkConfig struct {
+	// Starts holds start cells of walkers (out-of-bounds ones are skipped), defaults to single walker at map center.
+	Starts []image.Point
+	// Dirs holds walkers steps, defaults to cardinal directions (see [DirectionsCardinal]).
+	Dirs []image.Point
+	// Target is a cell, walkers are biased towards, walkers that reach it stop.
+	Target image.Point
+	// Bias is a chance (in range [0, 1]) to step towards target, target is ignored for zero.
+	Bias float64
+	// Coverage is a fraction of map cells (in range (0, 1]) to carve, defaults to 0.4.
+	Coverage float64
+	// MaxSteps is a limit of total steps of all walkers, defaults to 100 steps for every map cell.
+	MaxSteps int
+}
+
+// RandomWalk carves map by random walkers (drunkard's walk): every walker carves its cell, then steps in random
+// direction (or towards target, see [WalkConfig]), staying in map bounds. Walkers move in turns, until carved
+// cells reach coverage, all walkers reach target or steps are over. It returns number of carved cells.
+func (m *Map[T]) RandomWalk(
+	src rand.Source,
+	cfg WalkConfig,
+	floor func() T,
+) (carved int) {
+	const (
+		defaultCoverage = 0.4
+		stepsPerCell    = 100
+	)
+
+	var (
+		rng     = rand.New(src)
+		w, h    = m.cells.Bounds()
+		seen    = make([]bool, w*h)
+		walkers = m.walkStarts(cfg.Starts)
+	)
+
+	cfg.Coverage = orDefault(cfg.Coverage, defaultCoverage)
+	cfg.MaxSteps = orDefault(cfg.MaxSteps, stepsPerCell*w*h)
+
+	if len(cfg.Dirs) == 0 {
+		cfg.Dirs = Points(DirectionsCardinal...)
+	}
+
+	var (
+		goal = int(cfg.Coverage * float64(w*h))
+		done = func() bool { return carved >= goal || len(walkers) == 0 }
+	)
+
+	carve := func(p image.Point) {
+		if i := p.Y*w + p.X; !seen[i] {
+			seen[i] = true
+			carved++
+		}
+
+		m.Set(p, floor())
+	}
+
+	for _, p := range walkers {
+		carve(p)
+	}
+
+	for steps := 0; steps < cfg.MaxSteps && !done(); {
+		for i := 0; i < len(walkers) && steps < cfg.MaxSteps && !done(); i++ {
+			if cfg.Bias > 0 && walkers[i] == cfg.Target {
+				walkers = slices.Delete(walkers, i, i+1)
+				i--
+
+				continue
+			}
+
+			walkers[i] = m.walkStep(rng, walkers[i], &cfg)
+			carve(walkers[i])
+
+			steps++
+		}
+	}
+
+	return carved
+}
+
+// walkStarts returns in-bounds start cells (map center, if none given), there are none for empty map.
+func (m *Map[T]) walkStarts(starts []image.Point) (rv []image.Point) {
+	const two = 2
+
+	if len(starts) == 0 {
+		starts = []image.Point{m.rc.Max.Div(two)}
+	}
+
+	for _, p := range starts {
+		if p.In(m.rc) {
+			rv = append(rv, p)
+		}
+	}
+
+	return rv
+}
+
+// walkStep returns next in-bounds cell for walker.
+func (m *Map[T]) walkStep(rng *rand.Rand, cur image.Point, cfg *WalkConfig) (next image.Point) {
+	var moves []image.Point
+
+	for _, d := range cfg.Dirs {
+		if p := cur.Add(d); p.In(m.rc) {
+			moves = append(moves, p)
+		}
+	}
+
+	if len(moves) == 0 {
+		return cur
+	}
+
+	if cfg.Bias > 0 && rng.Float64() < cfg.Bias {
+		best := DistanceEuclidean(moves[0], cfg.Target)
+		closest := []image.Point{moves[0]}
+
+		for _, p := range moves[1:] {
+			switch d := DistanceEuclidean(p, cfg.Target); {
+			case d < best:
+				best, closest = d, []image.Point{p}
+			case d == best:
+				closest = append(closest, p)
+			}
+		}
+
+		moves = closest
+	}
+
+	return moves[rng.IntN(len(moves))]
+}
@@ -0,0 +1,142 @@
+package grid
+
+import (
+	"image"
+	"math/rand/v2"
+	"testing"
+)
+
+func countFloors(m *Map[bool]) (rv int) {
+	m.Iter(func(_ image.Point, wall bool) bool {
+		if !wall {
+			rv++
+		}
+
+		return true
+	})
+
+	return rv
+}
+
+func TestMapRandomWalk(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 40, 30
+
+	for seed := uint64(1); seed <= 4; seed++ {
+		var (
+			m = New[bool](image.Rect(0, 0, W, H))
+			o = New[bool](image.Rect(0, 0, W, H))
+		)
+
+		m.Fill(isTrue)
+		o.Fill(isTrue)
+
+		carved := m.RandomWalk(rand.NewPCG(seed, seed), WalkConfig{}, isFalse)
+
+		if carved != W*H*4/10 || countFloors(m) != carved {
+			t.Fatalf("seed %d: carved %d floors %d", seed, carved, countFloors(m))
+		}
+
+		if m.MustGet(image.Pt(W/2, H/2)) {
+			t.Fatalf("seed %d: start not carved", seed)
+		}
+
+		if c := m.Components(Points(DirectionsCardinal...), isFloor); len(c.Sizes) != 1 {
+			t.Fatalf("seed %d: components %v", seed, c.Sizes)
+		}
+
+		o.RandomWalk(rand.NewPCG(seed, seed), WalkConfig{}, isFalse)
+
+		m.Iter(func(p image.Point, v bool) bool {
+			if o.MustGet(p) != v {
+				t.Fatalf("seed %d: not reproducible", seed)
+			}
+
+			return true
+		})
+	}
+}
+
+func TestMapRandomWalkWalkers(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 60, 20
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		cfg = WalkConfig{
+			Starts:   []image.Point{{X: 5, Y: 10}, {X: 55, Y: 10}, {X: -1, Y: 3}},
+			Dirs:     Points(DirectionsALL...),
+			Coverage: 0.05,
+		}
+	)
+
+	m.Fill(isTrue)
+
+	carved := m.RandomWalk(rand.NewPCG(1, 1), cfg, isFalse)
+
+	if carved != W*H*5/100 || m.MustGet(cfg.Starts[0]) || m.MustGet(cfg.Starts[1]) {
+		t.Fatal("carved:", carved)
+	}
+
+	if c := m.Components(Points(DirectionsALL...), isFloor); len(c.Sizes) != 2 {
+		t.Fatal("components:", c.Sizes)
+	}
+}
+
+func TestMapRandomWalkTarget(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 50, 30
+
+	var (
+		m   = New[bool](image.Rect(0, 0, W, H))
+		cfg = WalkConfig{
+			Starts:   []image.Point{{X: 0, Y: 0}},
+			Target:   image.Pt(W-1, H-1),
+			Bias:     0.7,
+			Coverage: 1,
+		}
+	)
+
+	m.Fill(isTrue)
+
+	carved := m.RandomWalk(rand.NewPCG(2, 2), cfg, isFalse)
+
+	if m.MustGet(cfg.Target) || carved > W*H/4 {
+		t.Fatal("river:", carved)
+	}
+
+	if c := m.Components(Points(DirectionsCardinal...), isFloor); len(c.Sizes) != 1 {
+		t.Fatal("components:", c.Sizes)
+	}
+}
+
+func TestMapRandomWalkSteps(t *testing.T) {
+	t.Parallel()
+
+	var cases = []struct {
+		Rect   image.Rectangle
+		Cfg    WalkConfig
+		Carved int
+	}{
+		// steps are over
+		{Rect: image.Rect(0, 0, 10, 10), Cfg: WalkConfig{MaxSteps: 1, Dirs: Points(East)}, Carved: 2},
+		// no way to go
+		{Rect: image.Rect(0, 0, 3, 3), Cfg: WalkConfig{Coverage: 1, MaxSteps: 5, Dirs: []image.Point{{X: 5}}}, Carved: 1},
+		// no walkers
+		{Rect: image.Rect(0, 0, 5, 5), Cfg: WalkConfig{Starts: []image.Point{{X: 9, Y: 9}}}, Carved: 0},
+		// empty maps
+		{Rect: image.Rect(0, 0, 0, 0), Carved: 0},
+		{Rect: image.Rect(0, 0, 0, 4), Carved: 0},
+	}
+
+	for i, tc := range cases {
+		m := New[bool](tc.Rect)
+
+		if carved := m.RandomWalk(rand.NewPCG(1, 1), tc.Cfg, isFalse); carved != tc.Carved {
+			t.Fatalf("case[%d]: carved %d", i, carved)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package grid
+
+import "image"
+
+type watcher interface {
+	changed(p image.Point)
+	reset()
+}
+
+func (m *Map[T]) watch(w watcher) {
+	m.watchers = append(m.watchers, w)
+}
+
+func (m *Map[T]) unwatch(w watcher) {
+	for i, x := range m.watchers {
+		if x == w {
+			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (m *Map[T]) notify(p image.Point) {
+	for _, w := range m.watchers {
+		w.changed(p)
+	}
+}
+
+func (m *Map[T]) notifyAll() {
+	for _, w := range m.watchers {
+		w.reset()
+	}
+}
@@ -0,0 +1,438 @@
+package grid
+
+import (
+	"errors"
+	"image"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+)
+
+var (
+	// ErrWFCContradiction is returned, when wave function collapse fails to find solution.
+	ErrWFCContradiction = errors.New("grid: wfc contradiction")
+	// ErrWFCNoTiles is returned, when wave function collapse model has no tiles.
+	ErrWFCNoTiles = errors.New("grid: wfc has no tiles")
+)
+
+// WFC is a wave function collapse model: set of weighted tiles and their allowed neighbours in every direction.
+type WFC[T comparable] struct {
+	values  []T
+	weights []float64
+	allow   [][][]uint64 // direction -> tile -> bitset of allowed neighbours
+	ruled   []bool       // directions with any rules
+}
+
+type wfcChoice struct {
+	cell, tile, trail int
+}
+
+type wfcState struct {
+	wave  []uint64
+	trail []uint64 // old bitsets
+	cells []int    // changed cells, in trail order
+	count []int
+	words int
+}
+
+// NewWFC creates empty simple-tiled [WFC] model.
+func NewWFC[T comparable]() (rv *WFC[T]) {
+	return &WFC[T]{
+		allow: make([][][]uint64, len(coords)),
+		ruled: make([]bool, len(coords)),
+	}
+}
+
+// NewWFCSample creates simple-tiled [WFC] model from example map: every distinct value becomes a tile, weighted
+// by its frequency, and cardinal neighbours found in example become allowed.
+func NewWFCSample[T comparable](sample *Map[T]) (rv *WFC[T]) {
+	rv = NewWFC[T]()
+
+	sample.Iter(func(p image.Point, v T) bool {
+		rv.Add(v, one)
+
+		for _, d := range DirectionsCardinal {
+			if n, ok := sample.Get(p.Add(coords[d])); ok {
+				rv.Allow(v, d, n)
+			}
+		}
+
+		return true
+	})
+
+	return rv
+}
+
+// NewWFCOverlapping creates overlapping [WFC] model from example map: every distinct NxN pattern of example becomes
+// a tile (weighted by its frequency), that writes its top-left value, patterns are allowed to be cardinal neighbours,
+// if they agree on overlapping cells. Patterns are compared by values, so T should be a small set of values.
+func NewWFCOverlapping[T comparable](sample *Map[T], n int) (rv *WFC[T]) {
+	rv = NewWFC[T]()
+
+	var (
+		w, h     = sample.Bounds()
+		patterns [][]T
+	)
+
+	for y := 0; y+n <= h; y++ {
+		for x := 0; x+n <= w; x++ {
+			pat := make([]T, 0, n*n)
+
+			sample.RectFilled(image.Rect(x, y, x+n, y+n), func(_ image.Point, v T) bool {
+				pat = append(pat, v)
+
+				return true
+			})
+
+			idx := slices.IndexFunc(patterns, func(p []T) bool { return slices.Equal(p, pat) })
+			if idx < 0 {
+				idx = len(patterns)
+				patterns = append(patterns, pat)
+				rv.addTile(pat[0], 0)
+			}
+
+			rv.weights[idx]++
+		}
+	}
+
+	for a := range patterns {
+		for b := range patterns {
+			for _, d := range DirectionsCardinal {
+				if overlaps(patterns[a], patterns[b], n, coords[d]) {
+					rv.allowTiles(a, d, b)
+				}
+			}
+		}
+	}
+
+	return rv
+}
+
+// Add adds tile with given weight, or increases weight of existing tile.
+func (w *WFC[T]) Add(tile T, weight float64) {
+	if i := slices.Index(w.values, tile); i >= 0 {
+		w.weights[i] += weight
+
+		return
+	}
+
+	w.addTile(tile, weight)
+}
+
+// Allow allows tile b to be placed next to tile a in given direction (and a next to b in opposite one), unknown
+// tiles are added with zero weight. Directions without any rules are not constrained.
+func (w *WFC[T]) Allow(a T, d dir, b T) {
+	ia, ib := slices.Index(w.values, a), slices.Index(w.values, b)
+
+	if ia < 0 {
+		ia = len(w.values)
+		w.addTile(a, 0)
+	}
+
+	if ib < 0 {
+		ib = len(w.values)
+		w.addTile(b, 0)
+	}
+
+	w.allowTiles(ia, d, ib)
+}
+
+// Generate fills map by wave function collapse. Cell with least options is collapsed first (to weighted random
+// tile), on contradiction last choices are undone and banned, up to given number of backtracks. Map is not modified
+// on failure.
+func (w *WFC[T]) Generate(
+	m *Map[T],
+	src rand.Source,
+	backtracks int,
+) (err error) {
+	if len(w.values) == 0 {
+		return ErrWFCNoTiles
+	}
+
+	var (
+		rng   = rand.New(src)
+		mw, _ = m.cells.Bounds()
+		words = (len(w.values) + wordBits - 1) / wordBits
+		st    = &wfcState{words: words}
+		cells = mw * m.rc.Dy()
+		full  = make([]uint64, words)
+	)
+
+	if cells == 0 {
+		return nil
+	}
+
+	for i := range w.values {
+		if w.weights[i] > 0 {
+			full[i/wordBits] |= 1 << (i % wordBits)
+		}
+	}
+
+	st.wave = make([]uint64, cells*words)
+	st.count = make([]int, cells)
+
+	for c := 0; c < cells; c++ {
+		copy(st.wave[c*words:], full)
+		st.count[c] = popcount(full)
+	}
+
+	all := make([]int, cells)
+
+	for c := range all {
+		all[c] = c
+	}
+
+	if st.count[0] == 0 || !w.propagate(st, mw, all) {
+		return ErrWFCContradiction
+	}
+
+	if !w.collapse(st, rng, mw, backtracks) {
+		return ErrWFCContradiction
+	}
+
+	for c := 0; c < cells; c++ {
+		m.Set(image.Pt(c%mw, c/mw), w.values[firstBit(st.bits(c))])
+	}
+
+	return nil
+}
+
+// collapse collapses cells one by one, until all of them have single tile, on contradiction last choices
+// are undone and banned, up to given number of backtracks.
+func (w *WFC[T]) collapse(
+	st *wfcState,
+	rng *rand.Rand,
+	mw, backtracks int,
+) (ok bool) {
+	var stack []wfcChoice
+
+	for {
+		cell := st.lowest(rng)
+		if cell < 0 {
+			return true
+		}
+
+		tile := w.pick(rng, st.bits(cell))
+		stack = append(stack, wfcChoice{cell: cell, tile: tile, trail: len(st.cells)})
+
+		only := make([]uint64, st.words)
+		only[tile/wordBits] = 1 << (tile % wordBits)
+
+		ok = st.set(cell, only) && w.propagate(st, mw, []int{cell})
+
+		for !ok {
+			if len(stack) == 0 || backtracks <= 0 {
+				return false
+			}
+
+			backtracks--
+
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			ok = w.ban(st, mw, last)
+		}
+	}
+}
+
+// ban undoes given choice and removes its tile from cell options.
+func (w *WFC[T]) ban(st *wfcState, mw int, c wfcChoice) (ok bool) {
+	st.undo(c.trail)
+
+	rest := slices.Clone(st.bits(c.cell))
+	rest[c.tile/wordBits] &^= 1 << (c.tile % wordBits)
+
+	return st.set(c.cell, rest) && w.propagate(st, mw, []int{c.cell})
+}
+
+func (w *WFC[T]) addTile(v T, weight float64) {
+	w.values = append(w.values, v)
+	w.weights = append(w.weights, weight)
+
+	words := (len(w.values) + wordBits - 1) / wordBits
+
+	for d := range w.allow {
+		w.allow[d] = append(w.allow[d], nil)
+
+		for t := range w.allow[d] {
+			for len(w.allow[d][t]) < words {
+				w.allow[d][t] = append(w.allow[d][t], 0)
+			}
+		}
+	}
+}
+
+func (w *WFC[T]) allowTiles(a int, d dir, b int) {
+	inv := d.Invert()
+
+	w.allow[d][a][b/wordBits] |= 1 << (b % wordBits)
+	w.allow[inv][b][a/wordBits] |= 1 << (a % wordBits)
+	w.ruled[d], w.ruled[inv] = true, true
+}
+
+// pick chooses weighted random tile from given set.
+func (w *WFC[T]) pick(rng *rand.Rand, set []uint64) (tile int) {
+	var total float64
+
+	for i := range w.values {
+		if hasBit(set, i) {
+			total += w.weights[i]
+		}
+	}
+
+	r := rng.Float64() * total
+
+	for i := range w.values {
+		if !hasBit(set, i) {
+			continue
+		}
+
+		if tile, r = i, r-w.weights[i]; r < 0 {
+			break
+		}
+	}
+
+	return tile
+}
+
+// propagate removes tiles, that are not allowed anymore, starting from neighbours of given cells.
+func (w *WFC[T]) propagate(st *wfcState, mw int, queue []int) (ok bool) {
+	var (
+		allowed = make([]uint64, st.words)
+		next    = make([]uint64, st.words)
+		rc      = image.Rect(0, 0, mw, len(st.count)/mw)
+	)
+
+	for len(queue) > 0 {
+		cell := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		src := image.Pt(cell%mw, cell/mw)
+
+		for d, p := range coords {
+			if !w.ruled[d] {
+				continue
+			}
+
+			if p = src.Add(p); !p.In(rc) {
+				continue
+			}
+
+			clear(allowed)
+
+			for t := range w.values {
+				if hasBit(st.bits(cell), t) {
+					for i, v := range w.allow[d][t] {
+						allowed[i] |= v
+					}
+				}
+			}
+
+			n := p.Y*mw + p.X
+
+			var changed bool
+
+			for i, v := range st.bits(n) {
+				next[i] = v & allowed[i]
+				changed = changed || next[i] != v
+			}
+
+			if !changed {
+				continue
+			}
+
+			if !st.set(n, next) {
+				return false
+			}
+
+			queue = append(queue, n)
+		}
+	}
+
+	return true
+}
+
+func (st *wfcState) bits(cell int) []uint64 {
+	return st.wave[cell*st.words : (cell+1)*st.words]
+}
+
+// set records old cell state in trail and updates it, it reports false if no options left.
+func (st *wfcState) set(cell int, v []uint64) (ok bool) {
+	st.trail = append(st.trail, st.bits(cell)...)
+	st.cells = append(st.cells, cell)
+
+	copy(st.bits(cell), v)
+	st.count[cell] = popcount(v)
+
+	return st.count[cell] > 0
+}
+
+// undo restores cells state, changed after given trail position.
+func (st *wfcState) undo(pos int) {
+	for i := len(st.cells) - 1; i >= pos; i-- {
+		old := st.trail[i*st.words : (i+1)*st.words]
+
+		copy(st.bits(st.cells[i]), old)
+		st.count[st.cells[i]] = popcount(old)
+	}
+
+	st.cells = st.cells[:pos]
+	st.trail = st.trail[:pos*st.words]
+}
+
+// lowest returns random not-collapsed cell with least options, or -1 if all cells are collapsed.
+func (st *wfcState) lowest(rng *rand.Rand) (cell int) {
+	var best, ties int
+
+	cell = -1
+
+	for c, n := range st.count {
+		switch {
+		case n <= 1:
+			continue
+		case cell < 0 || n < best:
+			cell, best, ties = c, n, 1
+		case n == best:
+			// reservoir sampling among ties
+			if ties++; rng.IntN(ties) == 0 {
+				cell = c
+			}
+		}
+	}
+
+	return cell
+}
+
+// overlaps reports if NxN pattern b, shifted by d, agrees with pattern a on common cells.
+func overlaps[T comparable](a, b []T, n int, d image.Point) bool {
+	for y := max(0, d.Y); y < min(n, n+d.Y); y++ {
+		for x := max(0, d.X); x < min(n, n+d.X); x++ {
+			if a[y*n+x] != b[(y-d.Y)*n+(x-d.X)] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func popcount(set []uint64) (rv int) {
+	for _, v := range set {
+		rv += bits.OnesCount64(v)
+	}
+
+	return rv
+}
+
+func hasBit(set []uint64, i int) bool {
+	return set[i/wordBits]&(1<<(i%wordBits)) != 0
+}
+
+// firstBit returns index of lowest set bit, set must not be empty.
+func firstBit(set []uint64) (i int) {
+	for set[i/wordBits] == 0 {
+		i += wordBits
+	}
+
+	return i + bits.TrailingZeros64(set[i/wordBits])
+}
@@ -0,0 +1,285 @@
+package grid
+
+import (
+	"errors"
+	"image"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkWFC verifies, that every cardinal pair of map cells is allowed.
+func checkWFC[T comparable](t *testing.T, m *Map[T], allowed func(a T, d dir, b T) bool) {
+	t.Helper()
+
+	m.Iter(func(p image.Point, v T) bool {
+		for _, d := range []dir{East, South} {
+			if n, ok := m.Get(p.Add(coords[d])); ok && !allowed(v, d, n) {
+				t.Fatalf("%v: %v -> %v not allowed", p, v, n)
+			}
+		}
+
+		return true
+	})
+}
+
+func TestWFCRules(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 12, 9
+
+	var (
+		w = NewWFC[byte]()
+		m = New[byte](image.Rect(0, 0, W, H))
+	)
+
+	w.Add('a', 1)
+	w.Add('b', 1)
+	w.Add('a', 1)
+
+	for _, d := range DirectionsCardinal {
+		w.Allow('a', d, 'b')
+	}
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	checkWFC(t, m, func(a byte, _ dir, b byte) bool { return a != b })
+
+	// unconstrained directions
+	w = NewWFC[byte]()
+	w.Allow('a', East, 'b')
+	w.Allow('b', East, 'a')
+	w.Add('a', 1)
+	w.Add('b', 1)
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	checkWFC(t, m, func(a byte, d dir, b byte) bool { return d != East || a != b })
+}
+
+func TestWFCSample(t *testing.T) {
+	t.Parallel()
+
+	const W, H = 16, 16
+
+	var (
+		sample = New[byte](image.Rect(0, 0, 6, 3))
+		pairs  = make(map[[3]byte]bool)
+		a, b   = New[byte](image.Rect(0, 0, W, H)), New[byte](image.Rect(0, 0, W, H))
+	)
+
+	for i, row := range []string{"~~..##", "~...##", "~~..#."} {
+		for x, c := range []byte(row) {
+			sample.Set(image.Pt(x, i), c)
+		}
+	}
+
+	sample.Iter(func(p image.Point, v byte) bool {
+		for _, d := range []dir{East, South} {
+			if n, ok := sample.Get(p.Add(coords[d])); ok {
+				pairs[[3]byte{v, byte(d), n}] = true
+			}
+		}
+
+		return true
+	})
+
+	w := NewWFCSample(sample)
+
+	for _, m := range []*Map[byte]{a, b} {
+		if err := w.Generate(m, rand.NewPCG(5, 5), 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkWFC(t, a, func(v byte, d dir, n byte) bool { return pairs[[3]byte{v, byte(d), n}] })
+
+	a.Iter(func(p image.Point, v byte) bool {
+		if v != b.MustGet(p) {
+			t.Fatalf("%v: not reproducible", p)
+		}
+
+		return true
+	})
+}
+
+func TestWFCOverlapping(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H = 14, 10
+		N    = 2
+	)
+
+	var (
+		sample   = New[byte](image.Rect(0, 0, 8, 8))
+		m        = New[byte](image.Rect(0, 0, W, H))
+		patterns [][]byte
+		window   = func(src *Map[byte], p image.Point) (rv []byte) {
+			src.RectFilled(image.Rectangle{Min: p, Max: p.Add(image.Pt(N, N))}, func(_ image.Point, v byte) bool {
+				rv = append(rv, v)
+
+				return true
+			})
+
+			return rv
+		}
+	)
+
+	// rooms with thick walls
+	sample.Iter(func(p image.Point, _ byte) bool {
+		c := byte('.')
+		if p.X%4 < 2 || p.Y%4 < 2 {
+			c = '#'
+		}
+
+		sample.Set(p, c)
+
+		return true
+	})
+
+	for y := 0; y+N <= 8; y++ {
+		for x := 0; x+N <= 8; x++ {
+			patterns = append(patterns, window(sample, image.Pt(x, y)))
+		}
+	}
+
+	if err := NewWFCOverlapping(sample, N).Generate(m, rand.NewPCG(3, 3), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	for y := 0; y+N <= H; y++ {
+		for x := 0; x+N <= W; x++ {
+			win := window(m, image.Pt(x, y))
+
+			if !slices.ContainsFunc(patterns, func(p []byte) bool { return slices.Equal(p, win) }) {
+				t.Fatalf("(%d, %d): window %q not in sample", x, y, win)
+			}
+		}
+	}
+}
+
+func TestWFCBacktrack(t *testing.T) {
+	t.Parallel()
+
+	const (
+		W, H  = 8, 8
+		tiles = 5
+	)
+
+	var (
+		rng   = rand.New(rand.NewPCG(13, 7))
+		w     = NewWFC[int]()
+		rules = make(map[[3]int]bool)
+	)
+
+	for i := 0; i < tiles; i++ {
+		w.Add(i, 1)
+	}
+
+	for a := 0; a < tiles; a++ {
+		for b := 0; b < tiles; b++ {
+			for _, d := range []dir{East, South} {
+				if rng.Float64() < 0.4 {
+					w.Allow(a, d, b)
+					rules[[3]int{a, int(d), b}] = true
+				}
+			}
+		}
+	}
+
+	for seed := uint64(1); seed <= 5; seed++ {
+		m := New[int](image.Rect(0, 0, W, H))
+		m.Fill(func() int { return -1 })
+
+		if err := w.Generate(m, rand.NewPCG(seed, seed), 0); !errors.Is(err, ErrWFCContradiction) {
+			t.Fatalf("seed %d: no contradiction", seed)
+		}
+
+		if m.MustGet(image.Pt(0, 0)) != -1 {
+			t.Fatalf("seed %d: map modified", seed)
+		}
+
+		if err := w.Generate(m, rand.NewPCG(seed, seed), 10000); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+
+		checkWFC(t, m, func(a int, d dir, b int) bool { return rules[[3]int{a, int(d), b}] })
+	}
+}
+
+func TestWFCErrors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m = New[int](image.Rect(0, 0, 4, 4))
+		w = NewWFC[int]()
+	)
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 10); !errors.Is(err, ErrWFCNoTiles) {
+		t.Fatal("no tiles:", err)
+	}
+
+	// only tile has no allowed east neighbours
+	w.Add(1, 1)
+	w.Allow(2, East, 2)
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 10); !errors.Is(err, ErrWFCContradiction) {
+		t.Fatal("impossible:", err)
+	}
+
+	// zero weight tiles only
+	w = NewWFC[int]()
+	w.Allow(1, East, 1)
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 10); !errors.Is(err, ErrWFCContradiction) {
+		t.Fatal("zero weights:", err)
+	}
+
+	// 2x1 map, three tiles, but only pair 1-2 may stay together, other tiles are ruled out by propagation
+	w = NewWFC[int]()
+	w.Add(0, 1)
+	w.Add(1, 1)
+	w.Add(2, 1)
+	w.Allow(1, East, 2)
+
+	m = New[int](image.Rect(0, 0, 2, 1))
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 10); err != nil || m.MustGet(image.Pt(0, 0)) != 1 {
+		t.Fatal("pair:", err)
+	}
+
+	// empty maps
+	for _, rc := range []image.Rectangle{image.Rect(0, 0, 0, 0), image.Rect(0, 0, 0, 3), image.Rect(0, 0, 3, 0)} {
+		if err := w.Generate(New[int](rc), rand.NewPCG(1, 1), 10); err != nil {
+			t.Fatal("empty:", rc, err)
+		}
+	}
+}
+
+func TestWFCManyTiles(t *testing.T) {
+	t.Parallel()
+
+	const tiles = 100
+
+	var (
+		m = New[int](image.Rect(0, 0, 10, 10))
+		w = NewWFC[int]()
+	)
+
+	// every tile must be followed by next one
+	for i := 0; i < tiles; i++ {
+		w.Add(i, 1)
+		w.Allow(i, East, (i+1)%tiles)
+	}
+
+	if err := w.Generate(m, rand.NewPCG(1, 1), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	checkWFC(t, m, func(a int, d dir, b int) bool { return d != East || b == (a+1)%tiles })
+}